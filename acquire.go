@@ -0,0 +1,71 @@
+package izidic
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// acquisition is an instance acquired by AcquireT.
+type acquisition struct {
+	name   string
+	closer io.Closer
+}
+
+// AcquireT resolves a service whose instance is an io.Closer of type T, like
+// *os.File or *sql.DB, and tracks it so that Close closes it, whichever way it
+// was created, sparing the caller its own cleanup:
+//
+//	db, err := izidic.AcquireT[*sql.DB](dic, "db")
+//
+// It returns an error naming both types if the instance is not a T. Instances
+// are closed once, even if acquired several times, in the reverse order of
+// their first acquisition.
+//
+// On a scope returned by WithContext, it tracks nothing: the scope already
+// closes the instances it built, and never closes those of its container.
+func AcquireT[T io.Closer](dic Container, name string) (T, error) {
+	var zero T
+	instance, err := dic.Service(name)
+	if err != nil {
+		return zero, err
+	}
+	typed, ok := instance.(T)
+	if !ok {
+		return zero, fmt.Errorf("service %q has type %T, not %s", name, instance, reflect.TypeOf((*T)(nil)).Elem())
+	}
+	c := baseOf(dic)
+	c.Lock()
+	defer c.Unlock()
+	if !c.scoped && !isAcquired(c.acquired, typed) {
+		c.acquired = append(c.acquired, acquisition{name: name, closer: typed})
+	}
+	return typed, nil
+}
+
+// isAcquired tells whether an instance is among acquired, without panicking
+// on types which are not comparable.
+func isAcquired(acquired []acquisition, closer io.Closer) bool {
+	if !reflect.TypeOf(closer).Comparable() {
+		return false
+	}
+	for _, a := range acquired {
+		if reflect.TypeOf(a.closer) == reflect.TypeOf(closer) && a.closer == closer {
+			return true
+		}
+	}
+	return false
+}
+
+// closeAcquired closes the acquired instances in reverse order.
+func closeAcquired(acquired []acquisition) error {
+	var errs []error
+	for i := len(acquired) - 1; i >= 0; i-- {
+		a := acquired[i]
+		if err := a.closer.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("closing service %q: %w", a.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,53 @@
+package izidic_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fgm/izidic"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAcquireT(t *testing.T) {
+	errFailed := errors.New("failed")
+	mu := sync.Mutex{}
+	var closed []string
+	dic := izidic.New()
+	dic.Register("db", func(izidic.Container) (any, error) {
+		return &closer{name: "db", mu: &mu, log: &closed}, nil
+	})
+	dic.Register("file", func(izidic.Container) (any, error) {
+		return &closer{name: "file", mu: &mu, log: &closed, err: errFailed}, nil
+	})
+	dic.Register("s1", s1)
+	dic.Freeze()
+
+	db, err := izidic.AcquireT[*closer](dic, "db")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if db.name != "db" {
+		t.Fatalf("got %q, but expected db", db.name)
+	}
+	if _, err := izidic.AcquireT[io.Closer](dic, "file"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := izidic.AcquireT[*closer](dic, "db"); err != nil { // Closed once.
+		t.Fatalf("unexpected error: %v", err)
+	}
+	const expected = `service "s1" has type string, not io.Closer`
+	if _, err := izidic.AcquireT[io.Closer](dic, "s1"); err == nil || err.Error() != expected {
+		t.Fatalf("got error %v, but expected %q", err, expected)
+	}
+
+	err = dic.Close()
+	if !errors.Is(err, errFailed) || !strings.Contains(err.Error(), `closing service "file"`) {
+		t.Fatalf("got error %v, but expected the file closing error", err)
+	}
+	if expected := []string{"file", "db"}; !cmp.Equal(closed, expected) {
+		t.Fatalf("unexpected closing: %s", cmp.Diff(closed, expected))
+	}
+}
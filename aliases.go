@@ -0,0 +1,46 @@
+package izidic
+
+import "fmt"
+
+// AliasParam makes alias a name for the parameter stored as target, so that
+// Param(alias) returns the value of target, e.g. to keep readers of a renamed
+// configuration key working during a migration.
+//
+// Aliases may target other aliases, and are resolved transitively. The target
+// need not be stored yet: an alias to a missing parameter fails at resolution.
+// Storing a parameter under the alias name later replaces the alias.
+//
+// It returns an error if alias is already a stored parameter, or if the alias
+// would create a loop.
+func (dic *container) AliasParam(alias, target string) error {
+	alias, target = dic.normalize(alias), dic.normalize(target)
+	dic.Lock()
+	defer dic.Unlock()
+	dic.mustBuild("alias parameters")
+	if _, found := dic.parameters[alias]; found {
+		return fmt.Errorf("cannot alias %q: it is a stored parameter", alias)
+	}
+	for name, found := target, true; found; name, found = dic.paramAliases[name] {
+		if name == alias {
+			return fmt.Errorf("cannot alias %q to %q: alias loop", alias, target)
+		}
+	}
+	dic.paramAliases[alias] = target
+	dic.names = nil
+	return nil
+}
+
+// paramTarget returns the name of the parameter an alias resolves to, or name
+// itself if it is not an alias.
+//
+// Callers must hold the lock.
+func (dic *container) paramTarget(name string) string {
+	// AliasParam prevents loops, so following aliases terminates.
+	for {
+		target, found := dic.paramAliases[name]
+		if !found {
+			return name
+		}
+		name = target
+	}
+}
@@ -0,0 +1,52 @@
+package izidic_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fgm/izidic"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestContainer_AliasParam(t *testing.T) {
+	dic := izidic.New()
+	if err := dic.AliasParam("old", "renamed"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := dic.AliasParam("renamed", "new"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := dic.AliasParam("dangling", "missing"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dic.Store("new", 42) // Stored after the alias.
+
+	for _, name := range []string{"new", "renamed", "old"} {
+		if actual := dic.MustParam(name); actual != 42 {
+			t.Fatalf("got %v for %s, but expected 42", actual, name)
+		}
+	}
+	if _, err := dic.Param("dangling"); fmt.Sprint(err) != `parameter alias "dangling": parameter not found: "missing"` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"dangling", "old", "renamed"}
+	if actual := dic.Names()["paramAliases"]; !cmp.Equal(actual, expected) {
+		t.Fatalf("unexpected aliases: %s", cmp.Diff(actual, expected))
+	}
+
+	tests := [...]struct {
+		name, alias, target, expected string
+	}{
+		{"stored", "new", "other", `cannot alias "new": it is a stored parameter`},
+		{"self", "self", "self", `cannot alias "self" to "self": alias loop`},
+		{"chained", "older", "old", "<nil>"},
+		{"transitive loop", "renamed", "old", `cannot alias "renamed" to "old": alias loop`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := dic.AliasParam(test.alias, test.target); fmt.Sprint(err) != test.expected {
+				t.Fatalf("got error %v, but expected %s", err, test.expected)
+			}
+		})
+	}
+}
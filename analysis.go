@@ -0,0 +1,320 @@
+package izidic
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FreezeWithAnalysis freezes the container after a discovery pass proving its
+// dependency graph acyclic, so that later resolutions skip cycle detection,
+// which otherwise walks the call stack on each instantiation.
+//
+// The discovery pass instantiates every service once in a throwaway copy of the
+// container, recording the services each one requests, and reports cycles with
+// their full path, like "a -> b -> a". The instances built during discovery are
+// discarded, so any side effect in service factories happens twice.
+//
+// This requires factories to be deterministic in which dependencies they resolve:
+// a factory requesting different services on later runs could introduce a cycle
+// the analysis did not see. Dependencies are recorded for Service, MustService,
+// ServiceN, MustServiceN, Services, and Into calls on the container passed to
+// the factory, and for parameters stored by StoreFromService read from it.
+//
+// On error, the container remains in the Building state.
+func (dic *container) FreezeWithAnalysis() error {
+	if err := dic.Validate(); err != nil {
+		return err
+	}
+	if dic.State() == Closed {
+		return errors.New("cannot freeze closed container")
+	}
+	d, names := dic.discovery()
+	for _, name := range names {
+		if _, err := d.resolve(name, nil); err != nil {
+			d.finish()
+			return fmt.Errorf("analysis failed: %w", err)
+		}
+	}
+	edges := d.finish()
+
+	dic.Lock()
+	defer dic.Unlock()
+	if dic.state == Closed {
+		return errors.New("cannot freeze closed container")
+	}
+	dic.edges = edges
+	dic.analyzed = true
+	dic.state = Frozen
+	return nil
+}
+
+// CheckCycles reports the first dependency cycle found, in service name order,
+// among the services registered on the container, with its full path, like
+// "circular dependency detected: a -> b -> a". It returns nil if none is found.
+//
+// Like FreezeWithAnalysis, it instantiates services in a throwaway copy of the
+// container, which remains untouched. Other errors are ignored, so a factory
+// failing before requesting a dependency hides the cycles through it.
+//
+// Dropping it in a unit test guards against introducing a cycle when adding services.
+func CheckCycles(dic Container) error {
+	d, names := baseOf(dic).discovery()
+	defer d.finish()
+	for _, name := range names {
+		if _, err := d.resolve(name, nil); errors.Is(err, ErrCircularDependency) {
+			return err
+		}
+	}
+	return nil
+}
+
+// DependencyMatrix returns the dependencies between services as an adjacency
+// structure, where matrix[a][b] is true if service a requests service b, for
+// architecture checks like computing fan-in and fan-out.
+//
+// It only reflects the dependencies recorded by FreezeWithAnalysis, and is nil
+// without them, or under WithIntrospection(false). Each call returns a new copy, which callers may modify.
+func (dic *container) DependencyMatrix() map[string]map[string]bool {
+	dic.RLock()
+	defer dic.RUnlock()
+	if dic.edges == nil || dic.opaque {
+		return nil
+	}
+	matrix := make(map[string]map[string]bool, len(dic.edges))
+	for name, deps := range dic.edges {
+		row := make(map[string]bool, len(deps))
+		for _, dep := range deps {
+			row[dep] = true
+		}
+		matrix[name] = row
+	}
+	return matrix
+}
+
+// discovery prepares a discovery over a throwaway copy of the container, and
+// returns the sorted names of the services to discover.
+func (dic *container) discovery() (*discovery, []string) {
+	dic.RLock()
+	defer dic.RUnlock()
+	d := &discovery{
+		dic:       dic.child(),
+		instances: make(map[string]any),
+		edges:     make(map[string][]string),
+	}
+	names := make([]string, 0, len(d.dic.serviceDefs))
+	for name := range d.dic.serviceDefs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return d, names
+}
+
+// discovery resolves services while recording their dependencies.
+//
+// Factories abandoned by RegisterWithTimeout may keep resolving services from
+// their goroutine, during the pass or after it returned, so the discovery is
+// locked, and refuses resolutions once finished.
+type discovery struct {
+	dic       *container
+	mu        sync.Mutex
+	done      bool // Set by finish.
+	instances map[string]any
+	edges     map[string][]string
+}
+
+// finish ends the discovery, and returns the recorded dependencies, sorted.
+func (d *discovery) finish() map[string][]string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.done {
+		d.done = true
+		for _, deps := range d.edges {
+			sort.Strings(deps)
+		}
+	}
+	return d.edges
+}
+
+func (d *discovery) resolve(name string, chain []string) (any, error) {
+	if len(chain) > 0 && chain[len(chain)-1] == name {
+		return nil, &selfError{name: name}
+	}
+	for i, requester := range chain {
+		if requester == name {
+			return nil, fmt.Errorf("%w: %s", ErrCircularDependency,
+				strings.Join(append(chain[i:], name), " -> "))
+		}
+	}
+	d.mu.Lock()
+	instance, found := d.instances[name]
+	done := d.done
+	d.mu.Unlock()
+	switch {
+	case done:
+		return nil, errDiscoveryDone
+	case found:
+		return instance, nil
+	}
+	fn, found := d.dic.serviceDefs[name]
+	if !found {
+		return nil, d.dic.notFound("service", name)
+	}
+	rec := &recorder{Container: d.dic, discovery: d, chain: append(chain[:len(chain):len(chain)], name)}
+	instance, err := fn(rec)
+	if err != nil {
+		return nil, fmt.Errorf("failed instantiating service %s: %w", name, err)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.done {
+		d.instances[name] = instance
+	}
+	return instance, nil
+}
+
+// errDiscoveryDone is returned to factories resolving services after the end of
+// the discovery pass which called them.
+var errDiscoveryDone = errors.New("dependency discovery already finished")
+
+// recorder is the Container passed to factories during discovery.
+type recorder struct {
+	Container
+	discovery *discovery
+	chain     []string // The services being resolved, the last one being the requester.
+}
+
+func (r *recorder) base() *container {
+	return baseOf(r.Container)
+}
+
+func (r *recorder) MustService(name string) any {
+	instance, err := r.Service(name)
+	if err != nil {
+		panic(err)
+	}
+	return instance
+}
+
+func (r *recorder) MustServiceN(name Name) any {
+	return r.MustService(string(name))
+}
+
+func (r *recorder) Service(name string) (any, error) {
+	name = r.base().normalize(name)
+	d := r.discovery
+	requester := r.chain[len(r.chain)-1]
+	d.mu.Lock()
+	if d.done {
+		d.mu.Unlock()
+		return nil, errDiscoveryDone
+	}
+	deps := d.edges[requester]
+	found := false
+	for _, dep := range deps {
+		if dep == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		d.edges[requester] = append(deps, name)
+	}
+	d.mu.Unlock()
+	return d.resolve(name, r.chain)
+}
+
+func (r *recorder) ServiceN(name Name) (any, error) {
+	return r.Service(string(name))
+}
+
+func (r *recorder) Services(names ...string) ([]any, error) {
+	instances := make([]any, len(names))
+	for i, name := range names {
+		instance, err := r.Service(name)
+		if err != nil {
+			return nil, fmt.Errorf("resolving service %d %q: %w", i, name, err)
+		}
+		instances[i] = instance
+	}
+	return instances, nil
+}
+
+func (r *recorder) Into(name string, target any) error {
+	ptr := reflect.ValueOf(target)
+	if ptr.Kind() != reflect.Pointer || ptr.IsNil() {
+		return fmt.Errorf("target for service %q must be a non-nil pointer, not %T", name, target)
+	}
+	instance, err := r.Service(name)
+	if err != nil {
+		return err
+	}
+	return assign(name, instance, ptr.Elem())
+}
+
+func (r *recorder) MustParam(name string) any {
+	p, err := r.Param(name)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func (r *recorder) MustParamN(name Name) any {
+	return r.MustParam(string(name))
+}
+
+// Param records the service providing a parameter stored by StoreFromService
+// as a dependency.
+func (r *recorder) Param(name string) (any, error) {
+	name = r.base().normalize(name)
+	service, derived := r.base().paramService(name)
+	if !derived {
+		return r.Container.Param(name)
+	}
+	instance, err := r.Service(service)
+	if err != nil {
+		return nil, fmt.Errorf("parameter %q from service %q: %w", name, service, err)
+	}
+	return instance, nil
+}
+
+func (r *recorder) ParamN(name Name) (any, error) {
+	return r.Param(string(name))
+}
+
+// Invalidate removes the cached instance of a service, and those of all services
+// depending on it, directly or transitively, so that the next resolution of any
+// of them rebuilds the affected part of the graph with fresh instances.
+// Services outside the affected part keep their instances.
+//
+// Dependencies are only known when recorded by FreezeWithAnalysis: otherwise,
+// only the named service is invalidated, and its dependents keep the instances
+// they were built with.
+func (dic *container) Invalidate(name string) {
+	name = dic.normalize(name)
+	dic.Lock()
+	defer dic.Unlock()
+	dependents := make(map[string][]string, len(dic.edges))
+	for requester, deps := range dic.edges {
+		for _, dep := range deps {
+			dependents[dep] = append(dependents[dep], requester)
+		}
+	}
+	queue := []string{name}
+	seen := map[string]bool{name: true}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		delete(dic.services, current)
+		for _, dependent := range dependents[current] {
+			if !seen[dependent] {
+				seen[dependent] = true
+				queue = append(queue, dependent)
+			}
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package izidic_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/fgm/izidic"
+	"github.com/google/go-cmp/cmp"
+)
+
+// dependent builds a service returning its name, after resolving its dependencies.
+func dependent(name string, deps ...string) izidic.Service {
+	return func(dic izidic.Container) (any, error) {
+		for _, dep := range deps {
+			if _, err := dic.Service(dep); err != nil {
+				return nil, err
+			}
+		}
+		return name, nil
+	}
+}
+
+func TestContainer_FreezeWithAnalysis(t *testing.T) {
+	dic := izidic.New()
+	dic.Register("s1", s1)
+	dic.Register("s2", s2)
+	if err := dic.FreezeWithAnalysis(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual := dic.State(); actual != izidic.Frozen {
+		t.Fatalf("got state %s, but expected %s", actual, izidic.Frozen)
+	}
+	// Discovery instances are discarded.
+	if actual := dic.Unused(); len(actual) != 2 {
+		t.Fatalf("got unused %v, but expected all services", actual)
+	}
+	if actual := dic.MustService("s2"); actual != "s1s2" {
+		t.Fatalf("got %#v, but expected %q", actual, "s1s2")
+	}
+}
+
+func TestContainer_FreezeWithAnalysis_Failures(t *testing.T) {
+	errFailed := errors.New("failed")
+	tests := [...]struct {
+		name     string
+		defs     map[string]izidic.Service
+		expected string
+	}{
+		{"cycle", map[string]izidic.Service{
+			"sA": dependent("sA", "sB"),
+			"sB": dependent("sB", "sC"),
+			"sC": dependent("sC", "sA"),
+			"sD": dependent("sD"),
+		}, "circular dependency detected: sA -> sB -> sC -> sA"},
+		{"self", map[string]izidic.Service{
+			"sA": dependent("sA", "sA"),
+		}, `service "sA" requested itself during instantiation`},
+		{"missing", map[string]izidic.Service{
+			"sA": dependent("sA", "sB"),
+		}, `service not found: "sB"`},
+		{"failing", map[string]izidic.Service{
+			"sA": func(izidic.Container) (any, error) { return nil, errFailed },
+		}, "failed instantiating service sA: failed"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dic := izidic.New()
+			for name, fn := range test.defs {
+				dic.Register(name, fn)
+			}
+			err := dic.FreezeWithAnalysis()
+			if err == nil || !strings.HasSuffix(err.Error(), test.expected) {
+				t.Fatalf("got error %v, but expected suffix %q", err, test.expected)
+			}
+			if actual := dic.State(); actual != izidic.Building {
+				t.Fatalf("got state %s, but expected %s", actual, izidic.Building)
+			}
+		})
+	}
+
+	dic := izidic.New()
+	_ = dic.Close()
+	if err := dic.FreezeWithAnalysis(); fmt.Sprint(err) != "cannot freeze closed container" {
+		t.Fatalf("got error %v on closed container", err)
+	}
+}
+
+func TestContainer_Invalidate(t *testing.T) {
+	// counted builds a service returning how many times it was built, after
+	// resolving its dependencies.
+	builds := map[string]int{}
+	counted := func(name string, deps ...string) izidic.Service {
+		return func(dic izidic.Container) (any, error) {
+			if _, err := dependent(name, deps...)(dic); err != nil {
+				return nil, err
+			}
+			builds[name]++
+			return builds[name], nil
+		}
+	}
+	register := func(dic izidic.Container) {
+		dic.Register("config", counted("config"))
+		dic.Register("client", counted("client", "config"))
+		dic.Register("app", counted("app", "client"))
+		dic.Register("other", counted("other"))
+	}
+	all := []string{"app", "client", "config", "other"}
+
+	tests := [...]struct {
+		name     string
+		freeze   func(izidic.Container)
+		expected map[string]int
+	}{
+		{"with edges", func(dic izidic.Container) { _ = dic.FreezeWithAnalysis() },
+			map[string]int{"app": 2, "client": 2, "config": 2, "other": 1}},
+		{"without edges", izidic.Container.Freeze,
+			map[string]int{"app": 1, "client": 1, "config": 2, "other": 1}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dic := izidic.New()
+			register(dic)
+			test.freeze(dic)
+			builds = map[string]int{}
+			for _, name := range all {
+				dic.MustService(name)
+			}
+			dic.Invalidate("config")
+			for _, name := range all {
+				if actual := dic.MustService(name); actual != test.expected[name] {
+					t.Errorf("got build %v for %s, but expected %d", actual, name, test.expected[name])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckCycles(t *testing.T) {
+	dic := izidic.New()
+	dic.Register("sA", dependent("sA", "sB"))
+	dic.Register("sB", dependent("sB", "sA"))
+	dic.Register("sC", dependent("sC", "missing"))
+	dic.Register("s1", s1)
+
+	err := izidic.CheckCycles(dic)
+	if !errors.Is(err, izidic.ErrCircularDependency) || !strings.HasSuffix(err.Error(), "sA -> sB -> sA") {
+		t.Fatalf("got error %v, but expected cycle sA -> sB -> sA", err)
+	}
+	if actual := dic.Unused(); len(actual) != 4 {
+		t.Fatalf("got unused %v, but the container should be untouched", actual)
+	}
+	if actual := dic.State(); actual != izidic.Building {
+		t.Fatalf("got state %s, but expected %s", actual, izidic.Building)
+	}
+
+	dic = izidic.New()
+	dic.Register("s1", s1)
+	dic.Register("s2", s2)
+	dic.Register("sC", dependent("sC", "missing"))
+	if err := izidic.CheckCycles(dic); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestContainer_DependencyMatrix(t *testing.T) {
+	dic := izidic.New()
+	dic.Register("s1", s1)
+	dic.Register("s2", s2)
+	dic.Register("s3", dependent("s3", "s1", "s2"))
+	if actual := dic.DependencyMatrix(); actual != nil {
+		t.Fatalf("got %v before analysis, but expected nil", actual)
+	}
+	if err := dic.FreezeWithAnalysis(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actual := dic.DependencyMatrix()
+	expected := map[string]map[string]bool{
+		"s2": {"s1": true},
+		"s3": {"s1": true, "s2": true},
+	}
+	if !cmp.Equal(actual, expected) {
+		t.Fatalf("unexpected matrix: %s", cmp.Diff(actual, expected))
+	}
+	actual["s2"]["s3"] = true
+	if again := dic.DependencyMatrix(); !cmp.Equal(again, expected) {
+		t.Fatalf("got matrix modified through a copy: %s", cmp.Diff(again, expected))
+	}
+}
+
+func TestContainer_DependencyMatrix_AllResolutions(t *testing.T) {
+	dic := izidic.New()
+	dic.Register("s1", s1)
+	dic.Register("s2", s2)
+	dic.StoreFromService("derived", "s1")
+	dic.Register("services", func(dic izidic.Container) (any, error) { return dic.Services("s1", "s2") })
+	dic.Register("into", func(dic izidic.Container) (any, error) {
+		var s string
+		return s, dic.Into("s2", &s)
+	})
+	dic.Register("param", func(dic izidic.Container) (any, error) { return dic.Param("derived") })
+	if err := dic.FreezeWithAnalysis(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actual := dic.DependencyMatrix()
+	expected := map[string]map[string]bool{
+		"into":     {"s2": true},
+		"param":    {"s1": true},
+		"s2":       {"s1": true},
+		"services": {"s1": true, "s2": true},
+	}
+	if !cmp.Equal(actual, expected) {
+		t.Fatalf("unexpected matrix: %s", cmp.Diff(actual, expected))
+	}
+}
@@ -0,0 +1,30 @@
+package izidic
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RegisterAsserting registers a service whose instance must implement T,
+// typically an interface, so that a mismatch is reported once by its
+// resolution, naming the service and T, instead of by type assertions
+// scattered across call sites.
+//
+// Since instances are only built on resolution, the check happens then, not
+// at registration.
+func RegisterAsserting[T any](dic Container, name string, fn Service) {
+	if fn == nil {
+		mustFactory(name, nil)
+	}
+	dic.Register(name, func(dic Container) (any, error) {
+		instance, err := fn(dic)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := instance.(T); !ok {
+			return nil, fmt.Errorf("service %q has type %T, which does not implement %s",
+				name, instance, reflect.TypeOf((*T)(nil)).Elem())
+		}
+		return instance, nil
+	})
+}
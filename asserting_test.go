@@ -0,0 +1,27 @@
+package izidic_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+func TestRegisterAsserting(t *testing.T) {
+	dic := izidic.New()
+	izidic.RegisterAsserting[fmt.Stringer](dic, "s1", s1)
+	izidic.RegisterAsserting[io.Writer](dic, "writer", func(izidic.Container) (any, error) {
+		return &strings.Builder{}, nil
+	})
+	dic.Freeze()
+
+	if _, err := dic.Service("writer"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	const expected = `service "s1" has type string, which does not implement fmt.Stringer`
+	if _, err := dic.Service("s1"); err == nil || !strings.Contains(err.Error(), expected) {
+		t.Fatalf("got error %v, but expected it to contain %q", err, expected)
+	}
+}
@@ -0,0 +1,34 @@
+package izidic
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrBudgetExceeded is the error wrapped by errors reporting an exhausted
+// resolution budget, as set by WithResolutionBudget.
+var ErrBudgetExceeded = errors.New("resolution budget exceeded")
+
+// checkBudget refuses to instantiate a service once the budget is exhausted.
+func (dic *container) checkBudget(name string) error {
+	dic.RLock()
+	defer dic.RUnlock()
+	if dic.budgetSpent <= dic.budget {
+		return nil
+	}
+	return fmt.Errorf("%w: %s spent, over %s, since instantiating service %q: refusing to instantiate service %q",
+		ErrBudgetExceeded, dic.budgetSpent, dic.budget, dic.budgetTripper, name)
+}
+
+// spend charges the time spent in the factory of a service, excluding the
+// resolution of its dependencies, to the budget.
+func (dic *container) spend(name string, d time.Duration) {
+	dic.Lock()
+	defer dic.Unlock()
+	exceeded := dic.budgetSpent > dic.budget
+	dic.budgetSpent += d
+	if !exceeded && dic.budgetSpent > dic.budget {
+		dic.budgetTripper = name
+	}
+}
@@ -0,0 +1,42 @@
+package izidic_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fgm/izidic"
+)
+
+func TestWithResolutionBudget(t *testing.T) {
+	// slow builds a service sleeping for d after resolving its dependencies.
+	slow := func(d time.Duration, deps ...string) izidic.Service {
+		return func(dic izidic.Container) (any, error) {
+			for _, dep := range deps {
+				if _, err := dic.Service(dep); err != nil {
+					return nil, err
+				}
+			}
+			time.Sleep(d)
+			return d, nil
+		}
+	}
+	dic := izidic.New(izidic.WithResolutionBudget(30 * time.Millisecond))
+	dic.Register("leaf", slow(20*time.Millisecond))
+	// Charged for its own 20ms, not for the leaf: the total then exceeds the budget.
+	dic.Register("root", slow(20*time.Millisecond, "leaf"))
+	dic.Register("late", slow(0))
+	dic.Freeze()
+
+	if _, err := dic.Service("root"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err := dic.Service("late")
+	if !errors.Is(err, izidic.ErrBudgetExceeded) {
+		t.Fatalf("got error %v, but expected it to wrap %v", err, izidic.ErrBudgetExceeded)
+	}
+	if !strings.Contains(err.Error(), `since instantiating service "root": refusing to instantiate service "late"`) {
+		t.Fatalf("got error %v, but expected it to name the services", err)
+	}
+}
@@ -0,0 +1,36 @@
+package izidic
+
+import "time"
+
+// RegisterCached registers a service whose instance is only reused for ttl after
+// being built, then rebuilt on the next resolution, sitting between the default
+// singleton services and services building a new instance on each resolution.
+//
+// Expiry is lazy: it is checked when resolving the service, not by a background
+// goroutine, so an expired instance stays in memory until the next resolution.
+// Concurrent resolutions on a frozen container are safe: when several of them
+// rebuild an expired service at the same time, they all receive the first
+// instance stored, like singleton services.
+func (dic *container) RegisterCached(name string, fn Service, ttl time.Duration) {
+	name = dic.normalize(name)
+	if !dic.register(name, fn, nil) {
+		return
+	}
+	dic.Lock()
+	defer dic.Unlock()
+	dic.ttls[name] = ttl
+}
+
+// instance returns the cached instance of a service, unless its TTL expired.
+//
+// Callers must hold the lock.
+func (dic *container) instance(name string) (any, bool) {
+	instance, found := dic.services[name]
+	if !found {
+		return nil, false
+	}
+	if ttl, cached := dic.ttls[name]; cached && dic.clock.Now().Sub(dic.built[name]) >= ttl {
+		return nil, false
+	}
+	return instance, true
+}
@@ -0,0 +1,50 @@
+package izidic_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fgm/izidic"
+)
+
+func TestContainer_RegisterCached(t *testing.T) {
+	const ttl = time.Minute
+	clock := &fakeClock{}
+	calls := 0
+	dic := izidic.New(izidic.WithClock(clock))
+	dic.RegisterCached("s", func(izidic.Container) (any, error) {
+		calls++
+		return calls, nil
+	}, ttl)
+	dic.Freeze()
+
+	first := dic.MustService("s")
+	if again := dic.MustService("s"); again != first {
+		t.Fatalf("got instance %v before expiry, but expected %v", again, first)
+	}
+	clock.Advance(ttl)
+	if rebuilt := dic.MustService("s"); rebuilt == first {
+		t.Fatalf("got instance %v after expiry, but expected a new one", rebuilt)
+	}
+	if calls != 2 {
+		t.Fatalf("got %d calls, but expected 2", calls)
+	}
+}
+
+func TestContainer_RegisterCached_Overridden(t *testing.T) {
+	calls := 0
+	fn := func(izidic.Container) (any, error) {
+		calls++
+		return calls, nil
+	}
+	dic := izidic.New()
+	dic.RegisterCached("s", fn, 0)
+	dic.Register("s", fn)
+	dic.Freeze()
+
+	dic.MustService("s")
+	dic.MustService("s")
+	if calls != 1 {
+		t.Fatalf("got %d calls, but expected a singleton", calls)
+	}
+}
@@ -0,0 +1,68 @@
+package izidic
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Call calls fn with the parameters or services named by names as its
+// positional arguments, to run ad-hoc tasks wired from the container:
+//
+//	results, err := izidic.Call(dic, func(l *log.Logger, name string) error {
+//		l.Println("Hello", name)
+//		return nil
+//	}, "logger", "name")
+//
+// Unlike Invoke, which matches arguments to type-keyed services by type, each
+// name is resolved as a service if one is defined with that name, and as a
+// parameter otherwise. Resolution errors name the index and name of the
+// failing argument, and prevent the call.
+//
+// Call returns the results of fn. If the last one is a non-nil error, it is
+// also returned as the error, so that error-returning functions only need their
+// error checked. Variadic functions are not supported.
+func Call(dic Container, fn any, names ...string) ([]reflect.Value, error) {
+	f := reflect.ValueOf(fn)
+	if f.Kind() != reflect.Func || f.IsNil() {
+		return nil, fmt.Errorf("cannot call %T: not a function", fn)
+	}
+	t := f.Type()
+	switch {
+	case t.IsVariadic():
+		return nil, fmt.Errorf("cannot call %s: variadic functions are not supported", t)
+	case t.NumIn() != len(names):
+		return nil, fmt.Errorf("cannot call %s with %d arguments", t, len(names))
+	}
+
+	services := dic.Names()["services"]
+	args := make([]reflect.Value, len(names))
+	for i, name := range names {
+		var instance any
+		var err error
+		normalized := baseOf(dic).normalize(name)
+		if j := sort.SearchStrings(services, normalized); j < len(services) && services[j] == normalized {
+			instance, err = dic.Service(name)
+		} else {
+			instance, err = dic.Param(name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("argument %d (%q): %w", i, name, err)
+		}
+		args[i] = reflect.New(t.In(i)).Elem()
+		if v := reflect.ValueOf(instance); v.IsValid() {
+			if !v.Type().AssignableTo(t.In(i)) {
+				return nil, fmt.Errorf("argument %d (%q): type %s is not assignable to %s", i, name, v.Type(), t.In(i))
+			}
+			args[i].Set(v)
+		}
+	}
+
+	results := f.Call(args)
+	if n := t.NumOut(); n > 0 && t.Out(n-1) == errorType {
+		if err, _ := results[n-1].Interface().(error); err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
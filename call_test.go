@@ -0,0 +1,53 @@
+package izidic_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+func TestCall(t *testing.T) {
+	errFailed := errors.New("failed")
+	dic := izidic.New()
+	dic.Store("name", "world")
+	dic.Register("s1", s1)
+	dic.Freeze()
+
+	results, err := izidic.Call(dic, func(s string, name string) string {
+		return s + " " + name
+	}, "s1", "name")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual := results[0].String(); actual != "s1 world" {
+		t.Fatalf("got %q, but expected %q", actual, "s1 world")
+	}
+
+	_, err = izidic.Call(dic, func(string) error { return errFailed }, "s1")
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("got error %v, but expected %v", err, errFailed)
+	}
+
+	checks := [...]struct {
+		name     string
+		fn       any
+		names    []string
+		expected string
+	}{
+		{"not a function", 42, nil, "not a function"},
+		{"variadic", func(...string) {}, []string{"s1"}, "variadic functions are not supported"},
+		{"arity", func(string) {}, nil, "with 0 arguments"},
+		{"missing", func(string, string) {}, []string{"s1", "missing"}, `argument 1 ("missing"): parameter not found`},
+		{"type", func(int) {}, []string{"name"}, `argument 0 ("name"): type string is not assignable to int`},
+	}
+	for _, check := range checks {
+		t.Run(check.name, func(t *testing.T) {
+			_, err := izidic.Call(dic, check.fn, check.names...)
+			if err == nil || !strings.Contains(err.Error(), check.expected) {
+				t.Fatalf("got error %v, but expected it to contain %q", err, check.expected)
+			}
+		})
+	}
+}
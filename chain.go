@@ -0,0 +1,167 @@
+package izidic
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Chain returns a Container resolving parameters and services from an ordered
+// list of containers, like defaults, environment, and overrides layers, using
+// the first one defining each name, without merging them:
+//
+//	dic := izidic.Chain(overrides, env, defaults)
+//
+// Service instances are cached in the layer defining them, and their factories
+// receive that layer, so their dependencies are resolved from it, not the chain.
+//
+// Service, Param, their Must and N variants, Services, Into, Names, and
+// NamesCached consult all layers, with Names returning the union of names
+// across layers, and so do functions resolving services through Service, like
+// Populate and Try. All other methods, including writes like Store and Register,
+// and lifecycle methods like Freeze and Close, only apply to the first container.
+//
+// It panics if no container is given.
+func Chain(containers ...Container) Container {
+	if len(containers) == 0 {
+		panic("Cannot chain zero containers")
+	}
+	return &chain{Container: containers[0], layers: containers}
+}
+
+// chain is the Container returned by Chain.
+type chain struct {
+	Container
+	layers []Container
+}
+
+// base returns the container of the first layer, to which other methods apply.
+func (c *chain) base() *container {
+	return baseOf(c.Container)
+}
+
+func (c *chain) MustParam(name string) any {
+	p, err := c.Param(name)
+	if err != nil {
+		baseOf(c.Container).must(err)
+		return nil
+	}
+	return p
+}
+
+func (c *chain) MustParamN(name Name) any {
+	return c.MustParam(string(name))
+}
+
+func (c *chain) MustService(name string) any {
+	instance, err := c.Service(name)
+	if err != nil {
+		baseOf(c.Container).must(err)
+		return nil
+	}
+	return instance
+}
+
+func (c *chain) MustServiceN(name Name) any {
+	return c.MustService(string(name))
+}
+
+// Names returns the union of the names in all the chained containers.
+func (c *chain) Names() map[string][]string {
+	union := make(map[string]map[string]bool)
+	for _, layer := range c.layers {
+		for section, names := range layer.Names() {
+			if union[section] == nil {
+				union[section] = make(map[string]bool)
+			}
+			for _, name := range names {
+				union[section][name] = true
+			}
+		}
+	}
+	dump := make(map[string][]string, len(union))
+	for section, names := range union {
+		dump[section] = make([]string, 0, len(names))
+		for name := range names {
+			dump[section] = append(dump[section], name)
+		}
+		sort.Strings(dump[section])
+	}
+	return dump
+}
+
+// NamesCached is not cached on a chain, since layers may change independently.
+func (c *chain) NamesCached() map[string][]string {
+	return c.Names()
+}
+
+func (c *chain) Param(name string) (any, error) {
+	for _, layer := range c.layers {
+		if baseOf(layer).hasParam(name) {
+			return layer.Param(name)
+		}
+	}
+	return c.Container.Param(name)
+}
+
+func (c *chain) ParamN(name Name) (any, error) {
+	return c.Param(string(name))
+}
+
+func (c *chain) Service(name string) (any, error) {
+	for _, layer := range c.layers {
+		if baseOf(layer).hasService(name) {
+			return layer.Service(name)
+		}
+	}
+	return c.Container.Service(name)
+}
+
+func (c *chain) ServiceN(name Name) (any, error) {
+	return c.Service(string(name))
+}
+
+func (c *chain) Services(names ...string) ([]any, error) {
+	instances := make([]any, len(names))
+	for i, name := range names {
+		instance, err := c.Service(name)
+		if err != nil {
+			return nil, fmt.Errorf("resolving service %d %q: %w", i, name, err)
+		}
+		instances[i] = instance
+	}
+	return instances, nil
+}
+
+func (c *chain) Into(name string, target any) error {
+	ptr := reflect.ValueOf(target)
+	if ptr.Kind() != reflect.Pointer || ptr.IsNil() {
+		return fmt.Errorf("target for service %q must be a non-nil pointer, not %T", name, target)
+	}
+	instance, err := c.Service(name)
+	if err != nil {
+		return err
+	}
+	return assign(name, instance, ptr.Elem())
+}
+
+// hasParam tells whether a parameter, parameter alias, or parameter derived
+// from a service is defined.
+func (dic *container) hasParam(name string) bool {
+	name = dic.normalize(name)
+	dic.RLock()
+	defer dic.RUnlock()
+	_, stored := dic.parameters[name]
+	_, aliased := dic.paramAliases[name]
+	_, derived := dic.paramServices[name]
+	return stored || aliased || derived
+}
+
+// hasService tells whether a service is defined.
+func (dic *container) hasService(name string) bool {
+	name = dic.normalize(name)
+	dic.RLock()
+	defer dic.RUnlock()
+	_, defined := dic.serviceDefs[name]
+	return defined
+}
@@ -0,0 +1,78 @@
+package izidic_test
+
+import (
+	"testing"
+
+	"github.com/fgm/izidic"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestChain(t *testing.T) {
+	defaults := izidic.New()
+	defaults.Store("port", 8080)
+	defaults.Store("host", "localhost")
+	defaults.Register("s1", s1)
+	defaults.Register("s2", s2)
+	defaults.Freeze()
+
+	overrides := izidic.New()
+	overrides.Store("port", 9090)
+	overrides.Register("s1", func(izidic.Container) (any, error) { return "o1", nil })
+	overrides.Freeze()
+
+	dic := izidic.Chain(overrides, defaults)
+	if actual := dic.MustParam("port"); actual != 9090 {
+		t.Fatalf("got port %v, but expected the override", actual)
+	}
+	if actual := dic.MustParam("host"); actual != "localhost" {
+		t.Fatalf("got host %v, but expected the default", actual)
+	}
+	if actual := dic.MustService("s1"); actual != "o1" {
+		t.Fatalf("got s1 %v, but expected the override", actual)
+	}
+	// s2 resolves its dependencies in its own layer.
+	if actual := dic.MustService("s2"); actual != "s1s2" {
+		t.Fatalf("got s2 %v, but expected s1s2", actual)
+	}
+	if actual := defaults.Unused(); len(actual) != 0 {
+		t.Fatalf("got unused %v, but expected instances cached in the defaults layer", actual)
+	}
+	if _, err := dic.Service("missing"); err == nil {
+		t.Fatal("expected an error for a missing service")
+	}
+
+	expected := map[string][]string{
+		"paramAliases": {},
+		"params":       {"host", "port"},
+		"services":     {"s1", "s2"},
+	}
+	if actual := dic.Names(); !cmp.Equal(actual, expected) {
+		t.Fatalf("unexpected names: %s", cmp.Diff(actual, expected))
+	}
+}
+
+func TestChain_AllResolutions(t *testing.T) {
+	defaults := izidic.New()
+	defaults.Register("s1", s1)
+	defaults.Register("s2", s2)
+	defaults.Freeze()
+	overrides := izidic.New()
+	overrides.Register("s1", func(izidic.Container) (any, error) { return "o1", nil })
+	overrides.Freeze()
+	dic := izidic.Chain(overrides, defaults)
+
+	if actual, err := dic.Services("s1", "s2"); err != nil || !cmp.Equal(actual, []any{"o1", "s1s2"}) {
+		t.Fatalf("got %v, %v, but expected the instances from their layers", actual, err)
+	}
+	var s2 string
+	if err := dic.Into("s2", &s2); err != nil || s2 != "s1s2" {
+		t.Fatalf("got %q, %v, but expected s1s2", s2, err)
+	}
+	var target struct {
+		S1 string `izidic:"s1"`
+		S2 string `izidic:"s2"`
+	}
+	if err := izidic.Populate(dic, &target); err != nil || target.S1 != "o1" || target.S2 != "s1s2" {
+		t.Fatalf("got %+v, %v, but expected the instances from their layers", target, err)
+	}
+}
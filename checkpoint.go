@@ -0,0 +1,93 @@
+package izidic
+
+// Checkpoint snapshots the definitions of a container in build mode, returning
+// a function restoring them, which reverts the parameters, aliases, tags,
+// secrets, and services defined or replaced after the checkpoint, for "try these
+// registrations, then undo" patterns in complex test setups.
+//
+// The snapshot covers all the parameter and service definitions, including
+// aliases, tags, declarations, metadata, cache durations, profiles, modules,
+// versioned and type-keyed services, but not the service instances: those
+// built in build mode remain cached after a restore. It also covers the
+// BeforeResolve and AfterResolve hooks, and the register interceptor, so hooks
+// added and interceptors set after the checkpoint do not survive a restore.
+//
+// Like other build mode operations, both checkpointing and restoring panic if
+// the container is not in the Building state. A restore function may be called
+// several times, restoring the same snapshot each time.
+func (dic *container) Checkpoint() func() {
+	dic.RLock()
+	defer dic.RUnlock()
+	dic.mustBuild("checkpoint")
+	snap := dic.definitions()
+	before, after, interceptor := copyHooks(dic.before), copyHooks(dic.after), dic.interceptor
+	return func() {
+		dic.Lock()
+		defer dic.Unlock()
+		dic.mustBuild("restore checkpoint")
+		// Copy to keep the snapshot intact for later restores.
+		dic.adopt(snap.definitions())
+		dic.before, dic.after, dic.interceptor = copyHooks(before), copyHooks(after), interceptor
+	}
+}
+
+// copyHooks copies a map of hooks, with hook lists at full capacity, so that
+// hooks appended to the copy do not write into the lists of the original.
+func copyHooks[H any](hooks map[string][]H) map[string][]H {
+	res := make(map[string][]H, len(hooks))
+	for name, list := range hooks {
+		res[name] = list[:len(list):len(list)]
+	}
+	return res
+}
+
+// definitions returns a container holding copies of the definition maps
+// snapshot by Checkpoint, and replaced by Swap.
+//
+// Callers must hold the lock.
+func (dic *container) definitions() *container {
+	versions := make(map[string]map[string]versionedService, len(dic.versions))
+	for name, vs := range dic.versions {
+		versions[name] = copyMap(vs) // Versions are added in place by RegisterVersioned.
+	}
+	return &container{
+		enums:         copyMap(dic.enums),
+		imports:       copyMap(dic.imports),
+		meta:          copyMap(dic.meta),
+		nilable:       copyMap(dic.nilable),
+		overrides:     copyMap(dic.overrides),
+		owners:        copyMap(dic.owners),
+		paramAliases:  copyMap(dic.paramAliases),
+		paramServices: copyMap(dic.paramServices),
+		paramTags:     copyMap(dic.paramTags),
+		parameters:    copyMap(dic.parameters),
+		profile:       dic.profile,
+		profiles:      copyMap(dic.profiles),
+		removed:       copyMap(dic.removed),
+		secrets:       copyMap(dic.secrets),
+		serviceDefs:   copyMap(dic.serviceDefs),
+		specs:         copyMap(dic.specs),
+		ttls:          copyMap(dic.ttls),
+		watchers:      copyMap(dic.watchers),
+
+		versions: versions,
+
+		typed:      copyMap(dic.typed),
+		typedOrder: append([]typeKey(nil), dic.typedOrder...),
+	}
+}
+
+// adopt replaces the definition maps of the container with those of defs.
+//
+// Callers must hold the lock.
+func (dic *container) adopt(defs *container) {
+	dic.enums, dic.imports, dic.meta, dic.nilable = defs.enums, defs.imports, defs.meta, defs.nilable
+	dic.overrides, dic.owners = defs.overrides, defs.owners
+	dic.paramAliases, dic.paramServices, dic.paramTags = defs.paramAliases, defs.paramServices, defs.paramTags
+	dic.parameters, dic.secrets, dic.serviceDefs = defs.parameters, defs.secrets, defs.serviceDefs
+	dic.profile, dic.profiles, dic.removed = defs.profile, defs.profiles, defs.removed
+	dic.specs, dic.ttls, dic.watchers = defs.specs, defs.ttls, defs.watchers
+	dic.versions, dic.typed, dic.typedOrder = defs.versions, defs.typed, defs.typedOrder
+	dic.sharedDefs, dic.sharedParams = false, false
+	dic.names = nil
+}
@@ -0,0 +1,101 @@
+package izidic_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fgm/izidic"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestContainer_Checkpoint(t *testing.T) {
+	dic := izidic.New()
+	dic.Store("port", 8080)
+	dic.Register("s1", s1)
+	restore := dic.Checkpoint()
+
+	dic.Store("port", 9090)
+	dic.Store("host", "localhost")
+	dic.Register("s2", s2)
+	if err := dic.AliasParam("p", "port"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	restore()
+
+	expected := map[string][]string{
+		"paramAliases": {},
+		"params":       {"port"},
+		"services":     {"s1"},
+	}
+	if actual := dic.Names(); !cmp.Equal(actual, expected) {
+		t.Fatalf("unexpected names after restore: %s", cmp.Diff(actual, expected))
+	}
+	if actual := dic.MustParam("port"); actual != 8080 {
+		t.Fatalf("got port %v, but expected the checkpointed value", actual)
+	}
+
+	dic.Register("s2", s2)
+	restore() // Restoring again uses the same snapshot.
+	if actual := dic.Names()["services"]; !cmp.Equal(actual, expected["services"]) {
+		t.Fatalf("unexpected services after second restore: %s", cmp.Diff(actual, expected["services"]))
+	}
+
+	dic.Freeze()
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected restoring a frozen container to panic")
+		}
+	}()
+	restore()
+}
+
+func TestContainer_Checkpoint_AllDefinitions(t *testing.T) {
+	dic := izidic.New()
+	dic.SetProfile("test")
+	restore := dic.Checkpoint()
+	dic.RegisterProfile("test", "db", func(izidic.Container) (any, error) { return "stub", nil })
+	dic.RegisterVersioned("api", "1.0.0", s1)
+	izidic.ProvideType(dic, func(izidic.Container) (*config, error) { return &config{}, nil })
+	dic.Remove("legacy", "v2")
+	restore()
+
+	// The profile override is gone, so a plain registration applies again.
+	dic.Register("db", func(izidic.Container) (any, error) { return "real", nil })
+	dic.RegisterVersioned("api", "0.9.0", s1)
+	dic.Freeze()
+	if actual, err := dic.Service("db"); err != nil || actual != "real" {
+		t.Fatalf("got %v, %v, but expected the plain registration", actual, err)
+	}
+	if _, err := dic.ServiceVersion("api", "1.0.0"); err == nil {
+		t.Fatal("version registered after the checkpoint survived the restore")
+	}
+	if _, err := izidic.Resolve[*config](dic); err == nil {
+		t.Fatal("type provided after the checkpoint survived the restore")
+	}
+	if _, err := dic.Service("legacy"); err == nil || strings.Contains(err.Error(), "removed") {
+		t.Fatalf("got error %v, but expected a plain not found error", err)
+	}
+}
+
+func TestContainer_Checkpoint_Hooks(t *testing.T) {
+	dic := izidic.New()
+	var calls []string
+	dic.BeforeResolve("s1", func() { calls = append(calls, "before") })
+	restore := dic.Checkpoint()
+
+	dic.BeforeResolve("s1", func() { calls = append(calls, "late before") })
+	dic.AfterResolve("s1", func(any, error) { calls = append(calls, "late after") })
+	dic.SetRegisterInterceptor(func(name string, fn izidic.Service) izidic.Service {
+		return func(izidic.Container) (any, error) { return "intercepted", nil }
+	})
+	restore()
+
+	dic.Register("s1", s1)
+	dic.Freeze()
+	if actual := dic.MustService("s1"); actual != "s1" {
+		t.Fatalf("got %v, but expected the interceptor set after the checkpoint to be removed", actual)
+	}
+	if expected := []string{"before"}; !cmp.Equal(calls, expected) {
+		t.Fatalf("unexpected hook calls: %s", cmp.Diff(calls, expected))
+	}
+}
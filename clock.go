@@ -0,0 +1,28 @@
+package izidic
+
+import "time"
+
+// Clock is the source of time for the time-dependent features of the container,
+// like RegisterCached expiry, RegisterWithTimeout deadlines, and RegisterWithRetry
+// backoffs, allowing tests to control time with a fake implementation.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the default Clock, using the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Clock returns the clock used by the container, which factories may use too,
+// so that tests controlling time through WithClock control them as well.
+func (dic *container) Clock() Clock {
+	return dic.clock
+}
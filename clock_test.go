@@ -0,0 +1,99 @@
+package izidic_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fgm/izidic"
+)
+
+// fakeClock is a Clock whose time only changes when advanced.
+type fakeClock struct {
+	sync.Mutex
+	now     time.Time
+	waiters []waiter
+}
+
+type waiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.Lock()
+	defer c.Unlock()
+	ch := make(chan time.Time, 1)
+	c.waiters = append(c.waiters, waiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward, firing the channels of elapsed waits.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = pending
+}
+
+// Waiting returns the number of pending waits.
+func (c *fakeClock) Waiting() int {
+	c.Lock()
+	defer c.Unlock()
+	return len(c.waiters)
+}
+
+func TestWithClock(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	dic := izidic.New(izidic.WithClock(clock))
+	dic.Register("now", func(dic izidic.Container) (any, error) {
+		return dic.Clock().Now(), nil
+	})
+	dic.Freeze()
+	if actual := dic.MustService("now"); actual != clock.now {
+		t.Fatalf("got %v, but expected the fake time %v", actual, clock.now)
+	}
+
+	if izidic.New().Clock() == nil {
+		t.Fatal("expected a default clock")
+	}
+}
+
+func TestWithClock_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	clock := &fakeClock{}
+	dic := izidic.New(izidic.WithClock(clock))
+	dic.RegisterWithTimeout("stuck", func(izidic.Container) (any, error) {
+		<-release
+		return nil, nil
+	}, time.Hour)
+	dic.Freeze()
+
+	errs := make(chan error)
+	go func() {
+		_, err := dic.Service("stuck")
+		errs <- err
+	}()
+	for clock.Waiting() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	clock.Advance(time.Hour)
+	if err := <-errs; err == nil {
+		t.Fatal("expected a timeout error")
+	}
+}
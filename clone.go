@@ -0,0 +1,107 @@
+package izidic
+
+import (
+	"runtime"
+	"time"
+)
+
+// CloneShared returns a new container in the Building state, with the same
+// parameters and service definitions as the container, but none of its
+// instances, e.g. to derive many near-identical per-tenant containers.
+//
+// To save memory, the clone and the container share their parameter and service
+// definition maps, until either of them stores a parameter or registers a
+// service: it then copies the map before modifying it, so changes on one side
+// are never visible on the other. The smaller maps, like metadata or parameter
+// aliases, are copied immediately.
+//
+// Unlike ServiceWith, which resolves a service in a temporary frozen copy, the
+// clone is a full container, which may diverge from the original by overriding
+// definitions. Dependencies recorded by FreezeWithAnalysis are not copied, since
+// they may not hold after divergence.
+func (dic *container) CloneShared() Container {
+	dic.Lock()
+	defer dic.Unlock()
+	dic.sharedDefs, dic.sharedParams = true, true
+	versions := make(map[string]map[string]versionedService, len(dic.versions))
+	for name, vs := range dic.versions {
+		versions[name] = copyMap(vs)
+	}
+	meta := make(map[string]map[string]any, len(dic.meta))
+	for name, m := range dic.meta {
+		meta[name] = copyMap(m)
+	}
+	clone := &container{
+		after:         copyMap(dic.after),
+		before:        copyMap(dic.before),
+		budget:        dic.budget,
+		built:         make(map[string]time.Time),
+		clock:         dic.clock,
+		closeOnSwap:   dic.closeOnSwap,
+		enums:         copyMap(dic.enums),
+		imports:       copyMap(dic.imports),
+		interceptor:   dic.interceptor,
+		logger:        dic.logger,
+		lockMetrics:   dic.lockMetrics,
+		lockThreshold: dic.lockThreshold,
+		manifested:    make(map[string]bool),
+		meta:          meta,
+		mustHandler:   dic.mustHandler,
+		nilable:       copyMap(dic.nilable),
+		normalizer:    dic.normalizer,
+		opaque:        dic.opaque,
+		overrides:     copyMap(dic.overrides),
+		owners:        copyMap(dic.owners),
+		paramAliases:  copyMap(dic.paramAliases),
+		paramServices: copyMap(dic.paramServices),
+		paramTags:     copyMap(dic.paramTags),
+		parameters:    dic.parameters,
+		profile:       dic.profile,
+		profiles:      copyMap(dic.profiles),
+		removed:       copyMap(dic.removed),
+		required:      append([]string(nil), dic.required...),
+		secrets:       copyMap(dic.secrets),
+		serviceDefs:   dic.serviceDefs,
+		services:      make(map[string]any),
+		sharedDefs:    true,
+		sharedParams:  true,
+		specs:         copyMap(dic.specs),
+		strict:        dic.strict,
+		timeout:       dic.timeout,
+		traced:        dic.traced,
+		ttls:          copyMap(dic.ttls),
+		warmup:        dic.warmup,
+		watchers:      copyMap(dic.watchers),
+
+		typed:          copyMap(dic.typed),
+		typedInstances: make(map[typeKey]any),
+		typedOrder:     append([]typeKey(nil), dic.typedOrder...),
+
+		versions:         versions,
+		versionInstances: make(map[string]any),
+	}
+	if dic.stacks != nil {
+		clone.stacks = make(map[string][]runtime.Frame)
+	}
+	return clone
+}
+
+// ownDefs copies the service definitions map before a change if it is shared.
+//
+// Callers must hold the lock.
+func (dic *container) ownDefs() {
+	if dic.sharedDefs {
+		dic.serviceDefs = copyMap(dic.serviceDefs)
+		dic.sharedDefs = false
+	}
+}
+
+// ownParams copies the parameters map before a change if it is shared.
+//
+// Callers must hold the lock.
+func (dic *container) ownParams() {
+	if dic.sharedParams {
+		dic.parameters = copyMap(dic.parameters)
+		dic.sharedParams = false
+	}
+}
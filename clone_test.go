@@ -0,0 +1,46 @@
+package izidic_test
+
+import (
+	"testing"
+
+	"github.com/fgm/izidic"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestContainer_CloneShared(t *testing.T) {
+	parent := izidic.New()
+	parent.Store("tenant", "default")
+	parent.Register("s1", s1)
+	parent.Register("s2", s2)
+
+	clone := parent.CloneShared()
+	clone.Store("tenant", "acme")
+	clone.Register("s1", func(izidic.Container) (any, error) { return "o1", nil })
+	parent.Store("region", "eu") // After cloning: not visible in the clone.
+	parent.Freeze()
+	clone.Freeze()
+
+	if actual := parent.MustParam("tenant"); actual != "default" {
+		t.Fatalf("got parent tenant %v, but expected default", actual)
+	}
+	if actual := clone.MustParam("tenant"); actual != "acme" {
+		t.Fatalf("got clone tenant %v, but expected acme", actual)
+	}
+	if actual := parent.MustService("s2"); actual != "s1s2" {
+		t.Fatalf("got parent s2 %v, but expected s1s2", actual)
+	}
+	if actual := clone.MustService("s2"); actual != "o1s2" {
+		t.Fatalf("got clone s2 %v, but expected o1s2", actual)
+	}
+	if _, err := clone.Param("region"); err == nil {
+		t.Fatal("expected the parameter stored in the parent after cloning to be missing in the clone")
+	}
+	expected := map[string][]string{
+		"paramAliases": {},
+		"params":       {"tenant"},
+		"services":     {"s1", "s2"},
+	}
+	if actual := clone.Names(); !cmp.Equal(actual, expected) {
+		t.Fatalf("unexpected clone names: %s", cmp.Diff(actual, expected))
+	}
+}
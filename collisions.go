@@ -0,0 +1,34 @@
+package izidic
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CheckNamespaceCollisions reports the names defined both as a parameter and as
+// a service, in name order, or returns nil if there are none.
+//
+// Parameters and services live in separate namespaces, so such collisions are
+// allowed, but they are almost always a mistake, like storing and registering
+// the same key, then getting surprising results from Param and Service.
+// Parameter aliases and parameters stored by StoreFromService count as
+// parameters.
+func (dic *container) CheckNamespaceCollisions() error {
+	dic.RLock()
+	defer dic.RUnlock()
+	var names []string
+	for name := range dic.serviceDefs {
+		_, stored := dic.parameters[name]
+		_, aliased := dic.paramAliases[name]
+		_, derived := dic.paramServices[name]
+		if stored || aliased || derived {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	return fmt.Errorf("names defined as both parameters and services: %s", strings.Join(names, ", "))
+}
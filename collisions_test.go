@@ -0,0 +1,30 @@
+package izidic_test
+
+import (
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+func TestContainer_CheckNamespaceCollisions(t *testing.T) {
+	dic := izidic.New()
+	dic.Store("port", 8080)
+	dic.Register("s1", s1)
+	if err := dic.CheckNamespaceCollisions(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dic.Store("s1", "shadowed")
+	dic.Register("port", s1)
+	dic.Register("p", s1)
+	if err := dic.AliasParam("p", "port"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	const expected = "names defined as both parameters and services: p, port, s1"
+	if err := dic.CheckNamespaceCollisions(); err == nil || err.Error() != expected {
+		t.Fatalf("got error %v, but expected %q", err, expected)
+	}
+	if err := dic.FreezeOrError(); err == nil || err.Error() != expected {
+		t.Fatalf("got error %v from FreezeOrError, but expected %q", err, expected)
+	}
+}
@@ -0,0 +1,65 @@
+package izidic
+
+import (
+	"fmt"
+	"reflect"
+)
+
+var containerType = reflect.TypeOf((*Container)(nil)).Elem()
+
+// Compose builds a T combining several typed facets over the same container.
+//
+// A facet is a struct embedding Container and adding typed accessors, like the
+// application container described in the README. T is a struct embedding such
+// facets, and optionally Container itself:
+//
+//	type LoggingFacet struct{ izidic.Container }
+//	func (f LoggingFacet) Logger() *log.Logger { return f.MustService("logger").(*log.Logger) }
+//
+//	type DBFacet struct{ izidic.Container }
+//	func (f DBFacet) DB() *sql.DB { return f.MustService("db").(*sql.DB) }
+//
+//	type AppContainer struct {
+//		izidic.Container // Makes Container methods unambiguous on AppContainer.
+//		LoggingFacet
+//		DBFacet
+//	}
+//
+//	app, err := izidic.Compose[AppContainer](dic)
+//
+// The accessors of all facets are promoted to T, while the Container methods,
+// which all facets share, remain reachable through the embedded Container.
+// Compose sets every Container field in T and in its struct fields, and returns
+// an error if T is not a struct or contains no facet.
+func Compose[T any](dic Container) (T, error) {
+	var res T
+	v := reflect.ValueOf(&res).Elem()
+	if v.Kind() != reflect.Struct {
+		return res, fmt.Errorf("cannot compose %s: not a struct", v.Type())
+	}
+	facets := 0
+	dv := reflect.ValueOf(&dic).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if !v.Type().Field(i).IsExported() {
+			continue
+		}
+		if field.Type() == containerType {
+			field.Set(dv)
+			continue
+		}
+		if field.Kind() != reflect.Struct {
+			continue
+		}
+		for j := 0; j < field.NumField(); j++ {
+			if field.Type().Field(j).IsExported() && field.Field(j).Type() == containerType {
+				field.Field(j).Set(dv)
+				facets++
+			}
+		}
+	}
+	if facets == 0 {
+		return res, fmt.Errorf("cannot compose %s: no facet embedding izidic.Container", v.Type())
+	}
+	return res, nil
+}
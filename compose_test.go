@@ -0,0 +1,58 @@
+package izidic_test
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+type fakeDB struct{ dsn string }
+
+type LoggingFacet struct{ izidic.Container }
+
+func (f LoggingFacet) Logger() *log.Logger { return f.MustService("logger").(*log.Logger) }
+
+type DBFacet struct{ izidic.Container }
+
+func (f DBFacet) DB() *fakeDB { return f.MustService("db").(*fakeDB) }
+
+type appContainer struct {
+	izidic.Container
+	LoggingFacet
+	DBFacet
+}
+
+func TestCompose(t *testing.T) {
+	dic := izidic.New()
+	dic.Store("dsn", "memory")
+	dic.Register("logger", func(izidic.Container) (any, error) {
+		return log.New(&bytes.Buffer{}, "", 0), nil
+	})
+	dic.Register("db", func(dic izidic.Container) (any, error) {
+		return &fakeDB{dsn: dic.MustParam("dsn").(string)}, nil
+	})
+	dic.Freeze()
+
+	app, err := izidic.Compose[appContainer](dic)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if app.Logger() != dic.MustService("logger") {
+		t.Fatal("logging facet does not return the container logger")
+	}
+	if actual := app.DB().dsn; actual != "memory" {
+		t.Fatalf("got DSN %q, but expected %q", actual, "memory")
+	}
+	if actual := app.MustParam("dsn"); actual != "memory" {
+		t.Fatalf("got %#v from the embedded container, but expected %q", actual, "memory")
+	}
+
+	if _, err := izidic.Compose[int](dic); err == nil {
+		t.Fatal("composing a non-struct did not fail")
+	}
+	if _, err := izidic.Compose[struct{ izidic.Container }](dic); err == nil {
+		t.Fatal("composing without facets did not fail")
+	}
+}
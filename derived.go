@@ -0,0 +1,68 @@
+package izidic
+
+import "fmt"
+
+// StoreFromService stores a parameter whose value is the instance of a service,
+// like a configuration object computed from several sources, for consumers
+// expecting a parameter: Param(paramName) then resolves serviceName.
+//
+// This blurs the distinction between parameters and services: the value is
+// built on first use, cached with the service, and rebuilt with it, as with
+// Invalidate or RegisterCached. It is listed by Names as a parameter, but is
+// not covered by parameter reports and validation, which only apply to stored
+// values.
+//
+// Storing a parameter under the same name later replaces it. A factory reading
+// a parameter derived from its own service fails with an error wrapping
+// ErrCircularDependency.
+func (dic *container) StoreFromService(paramName, serviceName string) {
+	paramName, serviceName = dic.normalize(paramName), dic.normalize(serviceName)
+	dic.Lock()
+	defer dic.Unlock()
+	dic.mustBuild("store parameters")
+	dic.ownParams()
+	delete(dic.parameters, paramName)
+	delete(dic.paramAliases, paramName)
+	dic.paramServices[paramName] = serviceName
+	dic.names = nil
+}
+
+// paramService returns the service providing a parameter stored by
+// StoreFromService, following aliases.
+func (dic *container) paramService(name string) (string, bool) {
+	dic.RLock()
+	defer dic.RUnlock()
+	service, derived := dic.paramServices[dic.paramTarget(name)]
+	return service, derived
+}
+
+func (v *factoryView) MustParam(name string) any {
+	p, err := v.Param(name)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func (v *factoryView) MustParamN(name Name) any {
+	return v.MustParam(string(name))
+}
+
+// Param resolves parameters derived from services through the view, so that
+// self-resolution, strict resolution, and resolution budgets apply to them.
+func (v *factoryView) Param(name string) (any, error) {
+	name = v.base().normalize(name)
+	service, derived := v.base().paramService(name)
+	if !derived {
+		return v.Container.Param(name)
+	}
+	instance, err := v.Service(service)
+	if err != nil {
+		return nil, fmt.Errorf("parameter %q from service %q: %w", name, service, err)
+	}
+	return instance, nil
+}
+
+func (v *factoryView) ParamN(name Name) (any, error) {
+	return v.Param(string(name))
+}
@@ -0,0 +1,45 @@
+package izidic_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+func TestContainer_StoreFromService(t *testing.T) {
+	type config struct{ port int }
+	builds := 0
+	dic := izidic.New()
+	dic.Register("config", func(izidic.Container) (any, error) {
+		builds++
+		return &config{port: 8080}, nil
+	})
+	dic.StoreFromService("cfg", "config")
+	if err := dic.AliasParam("settings", "cfg"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dic.Register("self", func(dic izidic.Container) (any, error) {
+		return dic.Param("loop")
+	})
+	dic.StoreFromService("loop", "self")
+	dic.StoreFromService("missing", "nowhere")
+	dic.Freeze()
+
+	cfg := dic.MustParam("cfg").(*config)
+	if cfg.port != 8080 {
+		t.Fatalf("got port %d, but expected 8080", cfg.port)
+	}
+	if actual := dic.MustParam("settings"); actual != cfg {
+		t.Fatalf("got %v through the alias, but expected the cached instance %v", actual, cfg)
+	}
+	if actual := dic.MustService("config"); actual != cfg || builds != 1 {
+		t.Fatalf("got %v after %d builds, but expected the instance shared with the parameter", actual, builds)
+	}
+	if _, err := dic.Service("self"); !errors.Is(err, izidic.ErrCircularDependency) {
+		t.Fatalf("got error %v, but expected a circular dependency", err)
+	}
+	if _, err := dic.Param("missing"); err == nil {
+		t.Fatal("expected an error for a parameter from a missing service")
+	}
+}
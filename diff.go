@@ -0,0 +1,137 @@
+package izidic
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ChangeKind describes how an entry differs between two containers.
+type ChangeKind string
+
+const (
+	Added   ChangeKind = "added"
+	Removed ChangeKind = "removed"
+	Changed ChangeKind = "changed"
+)
+
+// Change describes a single difference between two containers.
+//
+// Section is the Names() key for the entry: "paramAliases", "params", or
+// "services", or "paramTags" for the tags of parameters stored by StoreTagged.
+// Before and After hold the parameter types for changed parameters, the
+// providing services for changed parameters stored by StoreFromService, the
+// targets for changed parameter aliases, and the sorted, comma-separated tags
+// for changed parameter tags.
+type Change struct {
+	Kind    ChangeKind
+	Section string
+	Name    string
+	Before  string
+	After   string
+}
+
+// String formats the change as a single report line.
+func (c Change) String() string {
+	if c.Kind == Changed {
+		return fmt.Sprintf("%s %s %q: %s -> %s", c.Kind, c.Section, c.Name, c.Before, c.After)
+	}
+	return fmt.Sprintf("%s %s %q", c.Kind, c.Section, c.Name)
+}
+
+// Changes is the result of a container Diff, sorted by section, then name.
+type Changes []Change
+
+// String formats the changes as a readable report, one change per line.
+func (cs Changes) String() string {
+	lines := make([]string, len(cs))
+	for i, c := range cs {
+		lines[i] = c.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Diff compares the definitions of two containers, returning the parameters,
+// parameter aliases, parameter tags, and services added, removed, or changed
+// from a to b.
+//
+// Parameters are considered changed when their dynamic type differs, or when
+// the service providing them, for those stored by StoreFromService, differs.
+// Parameter aliases are considered changed when their target differs.
+// Service definitions are functions and cannot be compared, so they can only be added or removed.
+//
+// Diff reads the definitions of the containers and never resolves services.
+func Diff(a, b Container) Changes {
+	var changes Changes
+	ad, bd := baseOf(a).definedParams(), baseOf(b).definedParams()
+	sections := map[string][2]map[string]string{
+		"paramAliases": {ad.aliases, bd.aliases},
+		"params":       {ad.params, bd.params},
+		"paramTags":    {ad.tags, bd.tags},
+		"services":     {toSet(a.Names()["services"]), toSet(b.Names()["services"])},
+	}
+	for section, pair := range sections {
+		before, after := pair[0], pair[1]
+		for name, bv := range before {
+			av, ok := after[name]
+			switch {
+			case !ok:
+				changes = append(changes, Change{Kind: Removed, Section: section, Name: name})
+			case av != bv:
+				changes = append(changes, Change{Kind: Changed, Section: section, Name: name, Before: bv, After: av})
+			}
+		}
+		for name := range after {
+			if _, ok := before[name]; !ok {
+				changes = append(changes, Change{Kind: Added, Section: section, Name: name})
+			}
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Section != changes[j].Section {
+			return changes[i].Section < changes[j].Section
+		}
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
+
+// paramDefs describes the parameters of a container, for Diff.
+type paramDefs struct {
+	aliases map[string]string // Targets of parameter aliases.
+	params  map[string]string // Types of stored parameters, and services providing derived ones.
+	tags    map[string]string // Sorted, comma-separated tags of tagged parameters.
+}
+
+// definedParams describes the parameters of the container without resolving
+// the services providing parameters stored by StoreFromService.
+func (dic *container) definedParams() paramDefs {
+	dic.RLock()
+	defer dic.RUnlock()
+	defs := paramDefs{
+		aliases: copyMap(dic.paramAliases),
+		params:  make(map[string]string, len(dic.parameters)+len(dic.paramServices)),
+		tags:    make(map[string]string, len(dic.paramTags)),
+	}
+	for name, p := range dic.parameters {
+		defs.params[name] = fmt.Sprintf("%T", p)
+	}
+	for name, service := range dic.paramServices {
+		defs.params[name] = fmt.Sprintf("service %q", service)
+	}
+	for name, tags := range dic.paramTags {
+		sorted := append([]string(nil), tags...)
+		sort.Strings(sorted)
+		defs.tags[name] = strings.Join(sorted, ",")
+	}
+	return defs
+}
+
+// toSet maps names to empty descriptions, for sections where only presence is compared.
+func toSet(names []string) map[string]string {
+	set := make(map[string]string, len(names))
+	for _, name := range names {
+		set[name] = ""
+	}
+	return set
+}
@@ -0,0 +1,103 @@
+package izidic_test
+
+import (
+	"testing"
+
+	"github.com/fgm/izidic"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiff(t *testing.T) {
+	a := izidic.New()
+	a.Store("kept", "v")
+	a.Store("retyped", "8080")
+	a.Store("dropped", true)
+	a.Register("s1", s1)
+
+	b := izidic.New()
+	b.Store("kept", "w")
+	b.Store("retyped", 8080)
+	b.Store("new", 1.0)
+	b.Register("s1", s1)
+	b.Register("s2", s2)
+
+	actual := izidic.Diff(a, b)
+	expected := izidic.Changes{
+		{Kind: izidic.Removed, Section: "params", Name: "dropped"},
+		{Kind: izidic.Added, Section: "params", Name: "new"},
+		{Kind: izidic.Changed, Section: "params", Name: "retyped", Before: "string", After: "int"},
+		{Kind: izidic.Added, Section: "services", Name: "s2"},
+	}
+	if !cmp.Equal(actual, expected) {
+		t.Fatalf("unexpected changes: %s", cmp.Diff(actual, expected))
+	}
+
+	const report = `removed params "dropped"
+added params "new"
+changed params "retyped": string -> int
+added services "s2"`
+	if actual.String() != report {
+		t.Fatalf("got report:\n%s\nbut expected:\n%s", actual, report)
+	}
+
+	if actual := izidic.Diff(a, a); len(actual) != 0 {
+		t.Fatalf("got changes for identical containers: %v", actual)
+	}
+}
+
+func TestDiff_derivedAndAliases(t *testing.T) {
+	built := 0
+	config := func(izidic.Container) (any, error) {
+		built++
+		return "config", nil
+	}
+	a := izidic.New()
+	a.Store("port", 80)
+	a.Register("config", config)
+	a.Register("other", config)
+	a.StoreFromService("cfg", "config")
+	a.AliasParam("listen", "port")
+	a.AliasParam("gone", "port")
+
+	b := izidic.New()
+	b.Store("port", 80)
+	b.Store("altPort", 8080)
+	b.Register("config", config)
+	b.Register("other", config)
+	b.StoreFromService("cfg", "other")
+	b.AliasParam("listen", "altPort")
+
+	actual := izidic.Diff(a, b)
+	expected := izidic.Changes{
+		{Kind: izidic.Removed, Section: "paramAliases", Name: "gone"},
+		{Kind: izidic.Changed, Section: "paramAliases", Name: "listen", Before: "port", After: "altPort"},
+		{Kind: izidic.Added, Section: "params", Name: "altPort"},
+		{Kind: izidic.Changed, Section: "params", Name: "cfg", Before: `service "config"`, After: `service "other"`},
+	}
+	if !cmp.Equal(actual, expected) {
+		t.Fatalf("unexpected changes: %s", cmp.Diff(actual, expected))
+	}
+	if built != 0 {
+		t.Fatalf("Diff ran service factories %d times", built)
+	}
+}
+
+func TestDiff_Tags(t *testing.T) {
+	a := izidic.New()
+	a.StoreTagged("port", []string{"net", "http"}, 80)
+	a.StoreTagged("host", []string{"net"}, "localhost")
+	b := izidic.New()
+	b.StoreTagged("port", []string{"net"}, 80)
+	b.StoreTagged("host", []string{"net"}, "localhost")
+	b.StoreTagged("user", []string{"auth"}, "admin")
+
+	actual := izidic.Diff(a, b)
+	expected := izidic.Changes{
+		{Kind: izidic.Changed, Section: "paramTags", Name: "port", Before: "http,net", After: "net"},
+		{Kind: izidic.Added, Section: "paramTags", Name: "user"},
+		{Kind: izidic.Added, Section: "params", Name: "user"},
+	}
+	if !cmp.Equal(actual, expected) {
+		t.Fatalf("unexpected changes: %s", cmp.Diff(actual, expected))
+	}
+}
@@ -0,0 +1,94 @@
+package izidic
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// StoreEnum stores a string parameter constrained to a fixed set of values,
+// like a log level or an environment name, returning an error without storing
+// it if value is not one of them.
+//
+// The allowed values given by the first StoreEnum for a name are remembered,
+// and later ones are ignored: later StoreEnum calls for the name are checked
+// against them, and so are values stored with Store, StoreSecret, StoreTagged,
+// which panic on a value not allowed, and Update, which returns an error.
+// Values of string kinds, like a named type Level string, are compared by their
+// string value.
+//
+// The constraint applies in addition to any ParamSpec declared for the name
+// with DeclareParam.
+func (dic *container) StoreEnum(name string, value string, allowed []string) error {
+	name = dic.normalize(name)
+	dic.Lock()
+	defer dic.Unlock()
+	dic.mustBuild("store parameters")
+	if remembered, found := dic.enums[name]; found {
+		allowed = remembered
+	} else {
+		allowed = append([]string(nil), allowed...)
+		dic.enums[name] = allowed
+	}
+	if err := checkEnum(value, allowed, dic.secrets[name]); err != nil {
+		return fmt.Errorf("parameter %q: %w", name, err)
+	}
+	dic.ownParams()
+	dic.parameters[name] = value
+	delete(dic.paramAliases, name)
+	delete(dic.paramServices, name)
+	dic.names = nil
+	return nil
+}
+
+// ParamEnum returns the value of a string parameter, like one stored by StoreEnum.
+func (dic *container) ParamEnum(name string) (string, error) {
+	name = dic.normalize(name)
+	p, err := dic.Param(name)
+	if err != nil {
+		return "", err
+	}
+	value, ok := p.(string)
+	if !ok {
+		return "", fmt.Errorf("parameter %q: expected string, got %T", name, p)
+	}
+	return value, nil
+}
+
+// checkEnumParam checks a value for a parameter constrained by StoreEnum, if any,
+// without revealing the value of secret parameters.
+//
+// Callers must hold the lock.
+func (dic *container) checkEnumParam(name string, value any) error {
+	allowed, found := dic.enums[name]
+	if !found {
+		return nil
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.String {
+		return fmt.Errorf("expected string, got %T", value)
+	}
+	return checkEnum(v.String(), allowed, dic.secrets[name])
+}
+
+// mustEnum panics if a value violates the StoreEnum constraint of a parameter.
+//
+// Callers must hold the lock.
+func (dic *container) mustEnum(name string, value any) {
+	if err := dic.checkEnumParam(name, value); err != nil {
+		panic(fmt.Sprintf("Cannot store parameter %q: %v", name, err))
+	}
+}
+
+// checkEnum checks a value against the allowed ones, redacting it from the
+// error if it is secret.
+func checkEnum(value string, allowed []string, secret bool) error {
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+	}
+	if secret {
+		return fmt.Errorf("value %s not in allowed values %q", Redacted, allowed)
+	}
+	return fmt.Errorf("value %q not in allowed values %q", value, allowed)
+}
@@ -0,0 +1,110 @@
+package izidic_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+func TestContainer_StoreEnum(t *testing.T) {
+	levels := []string{"debug", "info", "error"}
+	dic := izidic.New()
+	if err := dic.StoreEnum("level", "info", levels); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual, err := dic.ParamEnum("level"); err != nil || actual != "info" {
+		t.Fatalf("got %q, %v, but expected info", actual, err)
+	}
+
+	const expected = `parameter "level": value "trace" not in allowed values ["debug" "info" "error"]`
+	// The remembered values apply, not the ones passed later.
+	if err := dic.StoreEnum("level", "trace", []string{"trace"}); fmt.Sprint(err) != expected {
+		t.Fatalf("got error %v, but expected %s", err, expected)
+	}
+	if actual := dic.MustParam("level"); actual != "info" {
+		t.Fatalf("got %v, but expected the rejected value not to be stored", actual)
+	}
+
+	func() {
+		defer func() {
+			const expected = `Cannot store parameter "level": value "trace" not in allowed values ["debug" "info" "error"]`
+			if rec := recover(); rec != expected {
+				t.Fatalf("got panic %#v, but expected %q", rec, expected)
+			}
+		}()
+		dic.Store("level", "trace")
+	}()
+	if actual := dic.MustParam("level"); actual != "info" {
+		t.Fatalf("got %v, but expected the rejected value not to be stored", actual)
+	}
+
+	dic.Store("port", 8080)
+	if _, err := dic.ParamEnum("port"); fmt.Sprint(err) != `parameter "port": expected string, got int` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+type level string
+
+func TestContainer_StoreEnum_NamedType(t *testing.T) {
+	dic := izidic.New()
+	if err := dic.StoreEnum("level", "info", []string{"debug", "info"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dic.Store("level", level("debug"))
+	if err := dic.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dic.Freeze()
+	if err := dic.Update("level", level("info")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	const expected = `parameter "level": value "trace" not in allowed values ["debug" "info"]`
+	if err := dic.Update("level", level("trace")); fmt.Sprint(err) != expected {
+		t.Fatalf("got error %v, but expected %s", err, expected)
+	}
+}
+
+func TestContainer_StoreEnum_DeclaredParam(t *testing.T) {
+	dic := izidic.New()
+	dic.DeclareParam("env", izidic.ParamSpec{Required: true, Validator: func(value any) error {
+		if value == "prod" {
+			return fmt.Errorf("not in tests")
+		}
+		return nil
+	}})
+	if err := dic.StoreEnum("env", "prod", []string{"dev", "prod"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Both the declared spec and the enum constraint apply.
+	if err := dic.Validate(); fmt.Sprint(err) != `parameter "env": not in tests` {
+		t.Fatalf("got error %v, but expected the declared validator to apply", err)
+	}
+	if err := dic.StoreEnum("env", "staging", nil); err == nil {
+		t.Fatal("value not allowed did not fail")
+	}
+}
+
+func TestContainer_StoreEnum_Secret(t *testing.T) {
+	dic := izidic.New()
+	if err := dic.StoreEnum("token", "dev", []string{"dev", "prod"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	func() {
+		defer func() {
+			if rec := fmt.Sprint(recover()); strings.Contains(rec, "s3cr3t") || !strings.Contains(rec, izidic.Redacted) {
+				t.Fatalf("got panic %s, but expected the value to be redacted", rec)
+			}
+		}()
+		dic.StoreSecret("token", "s3cr3t")
+	}()
+	if err := dic.StoreEnum("token", "s3cr3t", nil); err == nil || strings.Contains(err.Error(), "s3cr3t") {
+		t.Fatalf("got error %v, but expected the value to be redacted", err)
+	}
+	dic.DeclareParam("token", izidic.ParamSpec{})
+	if err := dic.Update("token", "s3cr3t"); err == nil || strings.Contains(err.Error(), "s3cr3t") {
+		t.Fatalf("got error %v, but expected the value to be redacted", err)
+	}
+}
@@ -8,7 +8,10 @@ import (
 )
 
 func main() {
-	dic := di.Resolve(os.Stdout, os.Args[0], os.Args[1:])
+	dic, err := di.Resolve(os.Stdout, os.Args[0], os.Args[1:])
+	if err != nil {
+		os.Exit(2) // The flag set already reported the error.
+	}
 	app := dic.MustService("app").(di.App)
 	log.Printf("app: %#v\n", app)
 	if err := app(); err != nil {
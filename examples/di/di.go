@@ -1,8 +1,10 @@
 package di
 
 import (
+	"flag"
 	"io"
 	"log"
+	"sync"
 
 	"github.com/fgm/izidic"
 )
@@ -19,6 +21,14 @@ func (c *Container) Logger() *log.Logger {
 	return c.MustService("logger").(*log.Logger)
 }
 
+// NamedLogger returns a logger writing to the "writer" parameter, with a
+// "[name] " prefix, for services to keep their output distinguishable.
+//
+// Loggers are created on first use, and the same one is returned for each name.
+func (c *Container) NamedLogger(name string) *log.Logger {
+	return c.MustService("loggers").(*namedLoggers).get(name)
+}
+
 // Name is a typed parameter accessor.
 func (c *Container) Name() string {
 	return c.MustParam("name").(string)
@@ -27,14 +37,23 @@ func (c *Container) Name() string {
 // Resolve is the location where the parameters and services in the container
 //
 //	are assembled and the container readied for use.
-func Resolve(w io.Writer, name string, args []string) izidic.Container {
+//
+// It returns the error from parsing args, after the flag set reported it.
+func Resolve(w io.Writer, name string, args []string) (izidic.Container, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.String("prefix", "", "The prefix for log lines")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
 	dic := izidic.New()
+	dic.StoreFromFlagSet(fs)
 	dic.Store("name", name)
 	dic.Store("writer", w)
 	dic.Register("app", appService)
 	dic.Register("logger", loggerService)
-	dic.Freeze()
-	return dic
+	dic.Register("loggers", loggersService)
+	return dic.Seal(), nil
 }
 
 func appService(dic izidic.Container) (any, error) {
@@ -52,6 +71,32 @@ func appService(dic izidic.Container) (any, error) {
 func loggerService(dic izidic.Container) (any, error) {
 	w := dic.MustParam("writer").(io.Writer)
 	log.SetOutput(w) // Support dependency code not taking an injected logger.
-	logger := log.New(w, "", log.LstdFlags)
+	prefix := dic.MustParam("prefix").(string)
+	logger := log.New(w, prefix, log.LstdFlags)
 	return logger, nil
 }
+
+// namedLoggers caches the loggers returned by Container.NamedLogger.
+type namedLoggers struct {
+	sync.Mutex
+	w       io.Writer
+	loggers map[string]*log.Logger
+}
+
+func (nl *namedLoggers) get(name string) *log.Logger {
+	nl.Lock()
+	defer nl.Unlock()
+	logger, found := nl.loggers[name]
+	if !found {
+		logger = log.New(nl.w, "["+name+"] ", log.LstdFlags)
+		nl.loggers[name] = logger
+	}
+	return logger
+}
+
+func loggersService(dic izidic.Container) (any, error) {
+	return &namedLoggers{
+		w:       dic.MustParam("writer").(io.Writer),
+		loggers: make(map[string]*log.Logger),
+	}, nil
+}
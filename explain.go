@@ -0,0 +1,120 @@
+package izidic
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Explanation describes a single service, as returned by Container.Explain.
+type Explanation struct {
+	Name         string
+	Instantiated bool
+	// Dependencies are the services the service requests, sorted by name.
+	Dependencies []string
+	// Dependents are the services requesting the service, sorted by name, only
+	// known when dependencies were recorded by FreezeWithAnalysis.
+	Dependents []string
+	Meta       map[string]any
+	Module     string   // The module owning the service, if any.
+	Versions   []string // The versions registered with RegisterVersioned, sorted.
+	// Duration is the time its first instantiation took, if it happened.
+	Duration time.Duration
+}
+
+// String renders the explanation as readable text, one property per line.
+func (e Explanation) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "service %q\n", e.Name)
+	fmt.Fprintf(&b, "  instantiated: %t", e.Instantiated)
+	if e.Instantiated && e.Duration > 0 {
+		fmt.Fprintf(&b, " in %s", e.Duration)
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "  dependencies: %s\n", list(e.Dependencies))
+	fmt.Fprintf(&b, "  dependents: %s\n", list(e.Dependents))
+	if e.Module != "" {
+		fmt.Fprintf(&b, "  module: %s\n", e.Module)
+	}
+	if len(e.Versions) > 0 {
+		fmt.Fprintf(&b, "  versions: %s\n", strings.Join(e.Versions, ", "))
+	}
+	keys := make([]string, 0, len(e.Meta))
+	for k := range e.Meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "  meta %s: %v\n", k, e.Meta[k])
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Explain describes a service, gathering what the container knows about it.
+//
+// Its dependencies come from FreezeWithAnalysis when it recorded them, which is
+// also the only way to know its dependents. Otherwise, they are discovered by
+// resolving the service in a throwaway copy of the container, like CheckCycles
+// does, so its factory and those of its dependencies run again there, and an
+// error during that discovery is returned. The container itself never resolves
+// anything for Explain.
+func (dic *container) Explain(name string) (Explanation, error) {
+	name = dic.normalize(name)
+	dic.RLock()
+	_, defined := dic.serviceDefs[name]
+	if !defined {
+		defer dic.RUnlock()
+		return Explanation{}, dic.notFound("service", name)
+	}
+	_, instantiated := dic.services[name]
+	e := Explanation{
+		Name:         name,
+		Instantiated: instantiated,
+		Meta:         copyMap(dic.meta[name]),
+		Module:       dic.owners[name],
+	}
+	for version := range dic.versions[name] {
+		e.Versions = append(e.Versions, version)
+	}
+	sort.Strings(e.Versions)
+	for _, entry := range dic.manifest {
+		if entry.Name == name {
+			e.Duration = entry.Duration
+			break
+		}
+	}
+	analyzed := dic.analyzed
+	if analyzed {
+		e.Dependencies = append([]string(nil), dic.edges[name]...)
+		for requester, deps := range dic.edges {
+			for _, dep := range deps {
+				if dep == name {
+					e.Dependents = append(e.Dependents, requester)
+				}
+			}
+		}
+		sort.Strings(e.Dependents)
+	}
+	dic.RUnlock()
+	if analyzed {
+		return e, nil
+	}
+
+	d, _ := dic.discovery()
+	_, err := d.resolve(name, nil)
+	edges := d.finish()
+	if err != nil {
+		return e, fmt.Errorf("discovering dependencies: %w", err)
+	}
+	e.Dependencies = edges[name]
+	return e, nil
+}
+
+// list formats names for Explanation.String.
+func list(names []string) string {
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,64 @@
+package izidic_test
+
+import (
+	"testing"
+
+	"github.com/fgm/izidic"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestContainer_Explain(t *testing.T) {
+	// build builds the same container, analyzed or not.
+	build := func(analyzed bool) izidic.Container {
+		dic := izidic.New()
+		dic.Register("s1", s1)
+		dic.RegisterWithMeta("s2", map[string]any{"owner": "team"}, s2)
+		dic.Register("s3", dependent("s3", "s2", "s1"))
+		if analyzed {
+			if err := dic.FreezeWithAnalysis(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		} else {
+			dic.Freeze()
+		}
+		return dic
+	}
+
+	dic := build(true)
+	dic.MustService("s2")
+	actual, err := dic.Explain("s2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := izidic.Explanation{
+		Name:         "s2",
+		Instantiated: true,
+		Dependencies: []string{"s1"},
+		Dependents:   []string{"s3"},
+		Meta:         map[string]any{"owner": "team"},
+	}
+	if !cmp.Equal(actual, expected, cmpopts.IgnoreFields(izidic.Explanation{}, "Duration")) {
+		t.Fatalf("unexpected explanation: %s", cmp.Diff(actual, expected))
+	}
+
+	dic = build(false)
+	actual, err = dic.Explain("s3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	const text = `service "s3"
+  instantiated: false
+  dependencies: s1, s2
+  dependents: none`
+	if actual.String() != text {
+		t.Fatalf("got explanation:\n%s\nbut expected:\n%s", actual, text)
+	}
+	if unused := dic.Unused(); len(unused) != 3 {
+		t.Fatalf("got unused %v, but expected discovery not to resolve in the container", unused)
+	}
+
+	if _, err := dic.Explain("missing"); err == nil {
+		t.Fatal("expected an error for a missing service")
+	}
+}
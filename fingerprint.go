@@ -0,0 +1,73 @@
+package izidic
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Fingerprint returns a stable hash of the container structure, for tests to
+// assert the wiring has not changed unexpectedly, like:
+//
+//	if fp := dic.Fingerprint(); fp != "3f2a...e1" {
+//		t.Fatalf("wiring changed: update the expected fingerprint to %s", fp)
+//	}
+//
+// It covers the names and dynamic types of parameters, the services providing
+// parameters stored by StoreFromService, the tags of parameters stored by
+// StoreTagged, parameter aliases and their targets, the names of services and
+// their versions, the modules owning services and their imports, type-keyed
+// providers, and the dependencies recorded by FreezeWithAnalysis, if any.
+//
+// It does not cover parameter values, service metadata, instances, or the code
+// of factories: changing what a factory does without changing the structure
+// leaves the fingerprint unchanged. For a readable comparison, see Diff.
+func (dic *container) Fingerprint() string {
+	dic.RLock()
+	defer dic.RUnlock()
+	var lines []string
+	for name, value := range dic.parameters {
+		lines = append(lines, fmt.Sprintf("param %q %T", name, value))
+	}
+	for name, service := range dic.paramServices {
+		lines = append(lines, fmt.Sprintf("derived %q %q", name, service))
+	}
+	for name, tags := range dic.paramTags {
+		for _, tag := range tags {
+			lines = append(lines, fmt.Sprintf("tag %q %q", name, tag))
+		}
+	}
+	for alias, target := range dic.paramAliases {
+		lines = append(lines, fmt.Sprintf("alias %q %q", alias, target))
+	}
+	for name := range dic.serviceDefs {
+		lines = append(lines, fmt.Sprintf("service %q", name))
+	}
+	for name, versions := range dic.versions {
+		for version := range versions {
+			lines = append(lines, fmt.Sprintf("version %q %q", name, version))
+		}
+	}
+	for name, module := range dic.owners {
+		lines = append(lines, fmt.Sprintf("owner %q %q", name, module))
+	}
+	for module, imports := range dic.imports {
+		lines = append(lines, fmt.Sprintf("module %q", module))
+		for imported := range imports {
+			lines = append(lines, fmt.Sprintf("import %q %q", module, imported))
+		}
+	}
+	for key := range dic.typed {
+		lines = append(lines, fmt.Sprintf("type %s", key))
+	}
+	for name, deps := range dic.edges {
+		for _, dep := range deps {
+			lines = append(lines, fmt.Sprintf("dep %q %q", name, dep))
+		}
+	}
+	sort.Strings(lines)
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:])
+}
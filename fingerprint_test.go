@@ -0,0 +1,64 @@
+package izidic_test
+
+import (
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+func TestContainer_Fingerprint(t *testing.T) {
+	// build builds the same structure each time, with the given port value.
+	build := func(port any) izidic.Container {
+		dic := izidic.New()
+		dic.Store("port", port)
+		dic.Register("s1", s1)
+		dic.Register("s2", s2)
+		return dic
+	}
+	base := build(8080).Fingerprint()
+	// Pinned, so that changes in the fingerprint format are deliberate.
+	const expected = "9f8a32ac2dba7a2a85113a035b9423c3064e0bbb0a7fcc7888a17d157a150367"
+	if base != expected {
+		t.Fatalf("got fingerprint %s, but expected %s", base, expected)
+	}
+	if actual := build(8080).Fingerprint(); actual != base {
+		t.Fatalf("got fingerprint %s, but expected the same %s", actual, base)
+	}
+	if actual := build(9090).Fingerprint(); actual != base {
+		t.Fatalf("got fingerprint %s for another value, but expected the same %s", actual, base)
+	}
+	if actual := build("8080").Fingerprint(); actual == base {
+		t.Fatal("got the same fingerprint for another parameter type")
+	}
+	tagged := func(tags ...string) string {
+		dic := build(8080)
+		dic.StoreTagged("port", tags, 8080)
+		return dic.Fingerprint()
+	}
+	if tagged("net") == base || tagged("net") == tagged("http") {
+		t.Fatal("got the same fingerprint with other parameter tags")
+	}
+	dic := build(8080)
+	dic.Register("s3", s1)
+	if actual := dic.Fingerprint(); actual == base {
+		t.Fatal("got the same fingerprint with another service")
+	}
+	dic = build(8080)
+	dic.StoreFromService("derived", "s1")
+	derived := dic.Fingerprint()
+	if derived == base {
+		t.Fatal("got the same fingerprint with a derived parameter")
+	}
+	dic = build(8080)
+	dic.StoreFromService("derived", "s2")
+	if actual := dic.Fingerprint(); actual == derived {
+		t.Fatal("got the same fingerprint with a derived parameter from another service")
+	}
+	dic = build(8080)
+	if err := dic.FreezeWithAnalysis(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual := dic.Fingerprint(); actual == base {
+		t.Fatal("got the same fingerprint with recorded dependencies")
+	}
+}
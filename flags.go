@@ -0,0 +1,23 @@
+package izidic
+
+import "flag"
+
+// StoreFromFlagSet stores each flag defined in a parsed flag set as a parameter
+// with the same name, whether set on the command line or left to its default,
+// so that command-line options override configuration in the container.
+//
+// Flags defined by the flag package functions, like fs.Int or fs.Duration,
+// implement flag.Getter, and are stored with their typed value: bool, int,
+// int64, uint, uint64, float64, string, or time.Duration. Custom flags only
+// implementing flag.Value are stored as the string from their String method.
+//
+// Like Store, it panics on a container which is not in the Building state.
+func (dic *container) StoreFromFlagSet(fs *flag.FlagSet) {
+	fs.VisitAll(func(f *flag.Flag) {
+		if getter, ok := f.Value.(flag.Getter); ok {
+			dic.Store(f.Name, getter.Get())
+			return
+		}
+		dic.Store(f.Name, f.Value.String())
+	})
+}
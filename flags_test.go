@@ -0,0 +1,48 @@
+package izidic_test
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/fgm/izidic"
+)
+
+// words is a custom flag.Value, without a Get method.
+type words []string
+
+func (w *words) String() string {
+	return "words"
+}
+
+func (w *words) Set(s string) error {
+	*w = append(*w, s)
+	return nil
+}
+
+func TestContainer_StoreFromFlagSet(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("port", 8080, "")
+	fs.Bool("verbose", false, "")
+	fs.Duration("timeout", time.Second, "")
+	fs.String("name", "default", "")
+	fs.Var(&words{}, "word", "")
+	if err := fs.Parse([]string{"-port", "9090", "-verbose", "-word", "a"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dic := izidic.New()
+	dic.StoreFromFlagSet(fs)
+	expected := map[string]any{
+		"port":    9090,
+		"verbose": true,
+		"timeout": time.Second,
+		"name":    "default",
+		"word":    "words",
+	}
+	for name, value := range expected {
+		if actual := dic.MustParam(name); actual != value {
+			t.Errorf("got %#v for %s, but expected %#v", actual, name, value)
+		}
+	}
+}
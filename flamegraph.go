@@ -0,0 +1,56 @@
+package izidic
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteFlameGraph writes the first instantiations of services in the folded
+// stacks format read by flamegraph.pl and speedscope, one line per service like
+// "api;repo;db 1234", to show where the startup time goes.
+//
+// Stacks follow the instantiation tree: a service appears below the one whose
+// factory instantiated it first, as listed in ManifestEntry.Built, so a shared
+// dependency only appears once, below the first service requesting it.
+// Each line ends with the self time of the service in microseconds: the time
+// spent in its factory, minus the time spent resolving services from the
+// container passed to it, as reported in ManifestEntry.Self.
+//
+// Concurrent instantiations, as with WarmupParallel with analysis, may build a
+// service on behalf of several factories at once: the tree is only reliable
+// for a sequential startup.
+func (dic *container) WriteFlameGraph(w io.Writer) error {
+	manifest := dic.InstantiationManifest()
+	entries := make(map[string]ManifestEntry, len(manifest))
+	children := make(map[string]bool)
+	for _, entry := range manifest {
+		entries[entry.Name] = entry
+		for _, name := range entry.Built {
+			children[name] = true
+		}
+	}
+
+	var write func(stack []string, entry ManifestEntry) error
+	write = func(stack []string, entry ManifestEntry) error {
+		stack = append(stack, entry.Name)
+		if _, err := fmt.Fprintf(w, "%s %d\n", strings.Join(stack, ";"), entry.Self.Microseconds()); err != nil {
+			return err
+		}
+		for _, name := range entry.Built {
+			if err := write(stack, entries[name]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, entry := range manifest {
+		if children[entry.Name] {
+			continue
+		}
+		if err := write(nil, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package izidic_test
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fgm/izidic"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestContainer_WriteFlameGraph(t *testing.T) {
+	const nap = 20 * time.Millisecond
+	dic := izidic.New()
+	dic.Register("db", func(izidic.Container) (any, error) {
+		time.Sleep(nap)
+		return "db", nil
+	})
+	dic.Register("cache", dependent("cache"))
+	dic.Register("repo", dependent("repo", "db", "cache"))
+	dic.Register("api", dependent("api", "repo", "cache"))
+	dic.Register("other", dependent("other", "cache"))
+	dic.Freeze()
+	dic.MustService("api")
+	dic.MustService("other")
+
+	buf := bytes.Buffer{}
+	if err := dic.WriteFlameGraph(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var stacks []string
+	selves := make(map[string]time.Duration)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		stack, us, _ := strings.Cut(line, " ")
+		n, err := strconv.Atoi(us)
+		if err != nil {
+			t.Fatalf("invalid line %q: %v", line, err)
+		}
+		stacks = append(stacks, stack)
+		selves[stack] = time.Duration(n) * time.Microsecond
+	}
+	expected := []string{"api", "api;repo", "api;repo;db", "api;repo;cache", "other"}
+	if !cmp.Equal(stacks, expected) {
+		t.Fatalf("unexpected stacks: %s", cmp.Diff(stacks, expected))
+	}
+	if self := selves["api;repo;db"]; self < nap {
+		t.Fatalf("got self time %s for db, but expected at least %s", self, nap)
+	}
+	if self := selves["api;repo"]; self >= nap {
+		t.Fatalf("got self time %s for repo, but expected it to exclude db", self)
+	}
+}
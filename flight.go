@@ -0,0 +1,93 @@
+package izidic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// flight is the instantiation of a service in progress, which concurrent
+// resolutions of the same service wait for, instead of running its factory
+// again and dropping all instances but the first one, which would then never
+// be closed.
+type flight struct {
+	done     chan struct{} // Closed when the flight lands.
+	instance any
+	err      error
+	waiting  string // The flight the factory is waiting for, if any.
+}
+
+// join returns the flight instantiating the service with the given key, and
+// whether the caller leads it, in which case it must run the factory, then land
+// the flight. Otherwise, it must wait for the flight, which is already landed
+// when the instance was cached meanwhile, as reported by cached.
+//
+// It reports a cycle instead of a flight which waits, directly or transitively,
+// for a service in chain, since that flight would never land.
+func (dic *container) join(key string, chain []string, cached func() (any, bool)) (f *flight, lead bool, err error) {
+	dic.Lock()
+	defer dic.Unlock()
+	if instance, found := cached(); found {
+		f = &flight{done: make(chan struct{}), instance: instance}
+		close(f.done)
+		return f, false, nil
+	}
+	if f, found := dic.flights[key]; found {
+		if err := dic.checkFlights(key, chain); err != nil {
+			return nil, false, err
+		}
+		if len(chain) > 0 {
+			if requester, found := dic.flights[chain[len(chain)-1]]; found {
+				requester.waiting = key
+			}
+		}
+		return f, false, nil
+	}
+	if dic.flights == nil {
+		dic.flights = make(map[string]*flight)
+	}
+	// The error remains if the factory panics, so that waiting callers fail too.
+	f = &flight{done: make(chan struct{}), err: fmt.Errorf("failed instantiating service %s: factory panicked", key)}
+	dic.flights[key] = f
+	return f, true, nil
+}
+
+// wait waits for a flight led by another caller to land, and returns its outcome.
+func (dic *container) wait(f *flight, chain []string) (any, error) {
+	<-f.done
+	if len(chain) > 0 {
+		dic.Lock()
+		if requester, found := dic.flights[chain[len(chain)-1]]; found {
+			requester.waiting = ""
+		}
+		dic.Unlock()
+	}
+	return f.instance, f.err
+}
+
+// land ends a flight led by the caller, releasing the callers waiting for it.
+func (dic *container) land(key string, f *flight) {
+	dic.Lock()
+	defer dic.Unlock()
+	if dic.flights[key] == f {
+		delete(dic.flights, key)
+	}
+	close(f.done)
+}
+
+// checkFlights reports a cycle if the flight with the given key waits, directly
+// or transitively, for a service in chain.
+//
+// Callers must hold the lock.
+func (dic *container) checkFlights(key string, chain []string) error {
+	path := []string{key}
+	for f := dic.flights[key]; f != nil && f.waiting != "" && len(path) <= len(dic.flights); f = dic.flights[f.waiting] {
+		path = append(path, f.waiting)
+		for i, requester := range chain {
+			if requester == f.waiting {
+				return fmt.Errorf("%s: %w",
+					strings.Join(append(chain[i:len(chain):len(chain)], path...), " -> "), ErrCircularDependency)
+			}
+		}
+	}
+	return nil
+}
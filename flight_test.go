@@ -0,0 +1,90 @@
+package izidic_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fgm/izidic"
+)
+
+func TestContainer_Service_SingleFlight(t *testing.T) {
+	var runs atomic.Int32
+	release := make(chan struct{})
+	dic := izidic.New()
+	dic.Register("db", func(izidic.Container) (any, error) {
+		runs.Add(1)
+		<-release
+		return &struct{ name string }{"db"}, nil
+	})
+	dic.RegisterVersioned("api", "1.0.0", func(izidic.Container) (any, error) {
+		runs.Add(1)
+		<-release
+		return "v1", nil
+	})
+	dic.RegisterVersioned("api", "2.0.0", s1)
+	dic.Freeze()
+
+	const callers = 8
+	instances := make([]any, 2*callers)
+	wg := sync.WaitGroup{}
+	for i := 0; i < callers; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			instances[i] = dic.MustService("db")
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			instances[callers+i], _ = dic.ServiceVersion("api", "1.0.0")
+		}(i)
+	}
+	time.Sleep(10 * time.Millisecond) // Let the callers pile up on the flights.
+	close(release)
+	wg.Wait()
+
+	if actual := runs.Load(); actual != 2 {
+		t.Fatalf("got %d factory runs, but expected one per service", actual)
+	}
+	for i := 1; i < callers; i++ {
+		if instances[i] != instances[0] || instances[callers+i] != instances[callers] {
+			t.Fatalf("got different instances at %d, but expected all callers to share them", i)
+		}
+	}
+}
+
+func TestContainer_Service_SingleFlightCycle(t *testing.T) {
+	aStarted, bStarted := make(chan struct{}), make(chan struct{})
+	dic := izidic.New()
+	dic.Register("a", func(dic izidic.Container) (any, error) {
+		close(aStarted)
+		<-bStarted
+		return dic.Service("b")
+	})
+	dic.Register("b", func(dic izidic.Container) (any, error) {
+		close(bStarted)
+		<-aStarted
+		return dic.Service("a")
+	})
+	dic.Freeze()
+
+	errs := make(chan error, 2)
+	for _, name := range []string{"a", "b"} {
+		go func(name string) {
+			_, err := dic.Service(name)
+			errs <- err
+		}(name)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errs:
+			if !errors.Is(err, izidic.ErrCircularDependency) {
+				t.Fatalf("got error %v, but expected a circular dependency", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("concurrent resolutions of a cycle deadlocked")
+		}
+	}
+}
@@ -1,5 +1,5 @@
 module github.com/fgm/izidic
 
-go 1.19
+go 1.21
 
 require github.com/google/go-cmp v0.5.9
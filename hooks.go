@@ -0,0 +1,28 @@
+package izidic
+
+// BeforeResolve adds a hook called before each run of the factory of a service,
+// e.g. to log exactly when a database connection starts, without wrapping it.
+//
+// Since instances are cached, hooks usually run once, but they run again if the
+// factory fails, or if the instance is rebuilt, as with Invalidate or Rebuild.
+// Hooks for services never instantiated never run.
+// Hooks for the same service run in the order they were added.
+func (dic *container) BeforeResolve(name string, fn func()) {
+	name = dic.normalize(name)
+	dic.Lock()
+	defer dic.Unlock()
+	dic.mustBuild("add hooks")
+	dic.before[name] = append(dic.before[name], fn)
+}
+
+// AfterResolve adds a hook called after each run of the factory of a service,
+// with its results, before they are wrapped or cached.
+//
+// It runs in the same conditions and order as BeforeResolve hooks.
+func (dic *container) AfterResolve(name string, fn func(instance any, err error)) {
+	name = dic.normalize(name)
+	dic.Lock()
+	defer dic.Unlock()
+	dic.mustBuild("add hooks")
+	dic.after[name] = append(dic.after[name], fn)
+}
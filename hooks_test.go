@@ -0,0 +1,31 @@
+package izidic_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fgm/izidic"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestContainer_Hooks(t *testing.T) {
+	var calls []string
+	dic := izidic.New()
+	dic.Register("s1", s1)
+	dic.Register("s2", s2)
+	dic.BeforeResolve("s2", func() { calls = append(calls, "before s2 1") })
+	dic.BeforeResolve("s2", func() { calls = append(calls, "before s2 2") })
+	dic.AfterResolve("s2", func(instance any, err error) {
+		calls = append(calls, fmt.Sprint("after s2 ", instance, " ", err))
+	})
+	dic.BeforeResolve("s1", func() { calls = append(calls, "before s1") })
+	dic.BeforeResolve("unused", func() { calls = append(calls, "before unused") })
+	dic.Freeze()
+
+	dic.MustService("s2")
+	dic.MustService("s2")
+	expected := []string{"before s2 1", "before s2 2", "before s1", "after s2 s1s2 <nil>"}
+	if !cmp.Equal(calls, expected) {
+		t.Fatalf("unexpected hook calls: %s", cmp.Diff(calls, expected))
+	}
+}
@@ -0,0 +1,40 @@
+package izidic
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Into resolves a service and assigns it to the variable target points to,
+// avoiding a type assertion at the call site:
+//
+//	var logger *log.Logger
+//	err := dic.Into("logger", &logger)
+//
+// It returns an error if target is not a non-nil pointer, or if the service type
+// is not assignable to the type target points to.
+func (dic *container) Into(name string, target any) error {
+	ptr := reflect.ValueOf(target)
+	if ptr.Kind() != reflect.Pointer || ptr.IsNil() {
+		return fmt.Errorf("target for service %q must be a non-nil pointer, not %T", name, target)
+	}
+	instance, err := dic.Service(name)
+	if err != nil {
+		return err
+	}
+	return assign(name, instance, ptr.Elem())
+}
+
+// assign sets dst to a service instance, if its type allows it.
+func assign(name string, instance any, dst reflect.Value) error {
+	v := reflect.ValueOf(instance)
+	if !v.IsValid() { // nil instance
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	if !v.Type().AssignableTo(dst.Type()) {
+		return fmt.Errorf("service %q has type %s, not assignable to %s", name, v.Type(), dst.Type())
+	}
+	dst.Set(v)
+	return nil
+}
@@ -0,0 +1,58 @@
+package izidic_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+func TestContainer_Into(t *testing.T) {
+	dic := izidic.New()
+	dic.Register("logger", func(izidic.Container) (any, error) {
+		return log.New(&bytes.Buffer{}, "", 0), nil
+	})
+	dic.Register("writer", func(izidic.Container) (any, error) {
+		return &bytes.Buffer{}, nil
+	})
+	dic.Freeze()
+
+	var logger *log.Logger
+	if err := dic.Into("logger", &logger); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if logger != dic.MustService("logger") {
+		t.Fatalf("got %p, but expected the service instance", logger)
+	}
+
+	// Assignable to an interface.
+	var w io.Writer
+	if err := dic.Into("writer", &w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if w != dic.MustService("writer") {
+		t.Fatalf("got %p, but expected the service instance", w)
+	}
+
+	tests := [...]struct {
+		name     string
+		service  string
+		target   any
+		expected string
+	}{
+		{"not pointer", "logger", "x", `target for service "logger" must be a non-nil pointer, not string`},
+		{"nil pointer", "logger", (**log.Logger)(nil), `target for service "logger" must be a non-nil pointer, not **log.Logger`},
+		{"mismatch", "logger", new(string), `service "logger" has type *log.Logger, not assignable to string`},
+		{"missing", "missing", new(string), `service not found: "missing"`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := dic.Into(test.service, test.target); fmt.Sprint(err) != test.expected {
+				t.Fatalf("got error %v, but expected %q", err, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package izidic
+
+import (
+	"fmt"
+	"sort"
+)
+
+// EntryKind describes the kind of a container entry.
+type EntryKind string
+
+const (
+	KindAlias   EntryKind = "alias" // A parameter alias.
+	KindParam   EntryKind = "param"
+	KindService EntryKind = "service"
+)
+
+// Entry describes one entry in the container inventory.
+type Entry struct {
+	Name string
+	Kind EntryKind
+	// Instantiated is only set on services having a cached instance.
+	Instantiated bool
+	// Type is only set on stored parameters, to the dynamic type of their value.
+	Type string
+	// Service is only set on parameters stored by StoreFromService, to the
+	// service providing them, which Inventory does not resolve.
+	Service string
+}
+
+// Inventory describes all the entries defined on the container, sorted by kind,
+// then name, in a single consistent snapshot, as a base for dashboards and
+// other introspection reports.
+//
+// It does not resolve any parameter or service.
+func (dic *container) Inventory() []Entry {
+	dic.RLock()
+	defer dic.RUnlock()
+	entries := make([]Entry, 0, len(dic.paramAliases)+len(dic.parameters)+len(dic.paramServices)+len(dic.serviceDefs))
+	for name := range dic.paramAliases {
+		entries = append(entries, Entry{Name: name, Kind: KindAlias})
+	}
+	for name, value := range dic.parameters {
+		entries = append(entries, Entry{Name: name, Kind: KindParam, Type: fmt.Sprintf("%T", value)})
+	}
+	for name, service := range dic.paramServices {
+		entries = append(entries, Entry{Name: name, Kind: KindParam, Service: service})
+	}
+	for name := range dic.serviceDefs {
+		_, instantiated := dic.services[name]
+		entries = append(entries, Entry{Name: name, Kind: KindService, Instantiated: instantiated})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Kind != entries[j].Kind {
+			return entries[i].Kind < entries[j].Kind
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
@@ -0,0 +1,37 @@
+package izidic_test
+
+import (
+	"testing"
+
+	"github.com/fgm/izidic"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestContainer_Inventory(t *testing.T) {
+	dic := izidic.New()
+	dic.Store("port", 8080)
+	dic.Store("host", "localhost")
+	if err := dic.AliasParam("addr", "host"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dic.Register("s1", s1)
+	dic.Register("s2", s2)
+	dic.StoreFromService("derived", "s2")
+	dic.Freeze()
+	dic.MustService("s1")
+
+	expected := []izidic.Entry{
+		{Name: "addr", Kind: izidic.KindAlias},
+		{Name: "derived", Kind: izidic.KindParam, Service: "s2"},
+		{Name: "host", Kind: izidic.KindParam, Type: "string"},
+		{Name: "port", Kind: izidic.KindParam, Type: "int"},
+		{Name: "s1", Kind: izidic.KindService, Instantiated: true},
+		{Name: "s2", Kind: izidic.KindService},
+	}
+	if actual := dic.Inventory(); !cmp.Equal(actual, expected) {
+		t.Fatalf("unexpected inventory: %s", cmp.Diff(actual, expected))
+	}
+	if actual := dic.Unused(); !cmp.Equal(actual, []string{"s2"}) {
+		t.Fatalf("got unused %v, but expected the derived parameter not to be resolved", actual)
+	}
+}
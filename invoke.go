@@ -0,0 +1,143 @@
+package izidic
+
+import (
+	"fmt"
+	"reflect"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Invoke calls fn, resolving each of its parameters from the type-keyed services
+// registered with ProvideType, sparing constructors the manual resolution of
+// their dependencies:
+//
+//	err := izidic.Invoke(dic, func(logger *log.Logger, cfg *Config) (*Server, error) {
+//		return NewServer(logger, cfg), nil
+//	})
+//
+// A variadic parameter receives the service of its slice type if one is provided,
+// and no arguments otherwise. Errors resolving a provided one are returned.
+//
+// fn may return nothing, an error, a value, or a value and an error.
+// A returned value is stored as the type-keyed service for its static type,
+// which requires the container to be in the Building state and the type not to
+// be provided yet.
+func Invoke(dic Container, fn any) error {
+	c := baseOf(dic)
+	fv := reflect.ValueOf(fn)
+	ft := fv.Type()
+	if ft.Kind() != reflect.Func {
+		return fmt.Errorf("cannot invoke %s: not a function", ft)
+	}
+	var valueType reflect.Type
+	switch n := ft.NumOut(); {
+	case n > 2, n == 2 && ft.Out(1) != errorType:
+		return fmt.Errorf("cannot invoke %s: results must be a value, an error, or both", ft)
+	case n >= 1 && (n == 2 || ft.Out(0) != errorType):
+		valueType = ft.Out(0)
+	}
+
+	args := make([]reflect.Value, 0, ft.NumIn())
+	variadic := false
+	for i := 0; i < ft.NumIn(); i++ {
+		t := ft.In(i)
+		last := i == ft.NumIn()-1
+		if last && ft.IsVariadic() && !c.provides(typeKey{t: t}) {
+			break // No variadic arguments.
+		}
+		arg, err := c.resolveType(typeKey{t: t})
+		if err != nil {
+			return fmt.Errorf("cannot resolve parameter %d of type %s: %w", i, t, err)
+		}
+		v := reflect.ValueOf(arg)
+		if !v.IsValid() { // nil instance
+			v = reflect.Zero(t)
+		}
+		args = append(args, v)
+		variadic = last && ft.IsVariadic()
+	}
+	if variadic {
+		return call(c, fv.CallSlice(args), valueType)
+	}
+	return call(c, fv.Call(args), valueType)
+}
+
+// provides tells whether a type-keyed service is provided.
+func (dic *container) provides(k typeKey) bool {
+	dic.RLock()
+	defer dic.RUnlock()
+	_, defined := dic.typed[k]
+	return defined
+}
+
+// call handles the results of the function called by Invoke.
+func call(dic *container, results []reflect.Value, valueType reflect.Type) error {
+	if n := len(results); n > 0 && results[n-1].Type() == errorType && !results[n-1].IsNil() {
+		return results[n-1].Interface().(error)
+	}
+	if valueType == nil {
+		return nil
+	}
+	k := typeKey{t: valueType}
+	instance := results[0].Interface()
+	dic.Lock()
+	defer dic.Unlock()
+	if dic.state != Building {
+		return fmt.Errorf("cannot store result of type %s on %s container", valueType, dic.state)
+	}
+	if _, found := dic.typed[k]; found {
+		return fmt.Errorf("cannot store result: type %s is already provided", valueType)
+	}
+	dic.typed[k] = func(Container) (any, error) { return instance, nil }
+	dic.typedOrder = append(dic.typedOrder, k)
+	dic.typedInstances[k] = instance
+	return nil
+}
+
+// ProvideFn registers a service built by calling ctor, a constructor whose
+// parameters are resolved from the type-keyed services registered with
+// ProvideType, like Invoke does, sparing the factory boilerplate:
+//
+//	izidic.ProvideFn[App](dic, "app", func(l *log.Logger, cfg *Config) App {
+//		return NewApp(l, cfg)
+//	})
+//
+// ctor must return a value assignable to T, optionally followed by an error.
+// Its parameters are resolved when the service is, and the resolution error
+// names the type of the first parameter which could not be resolved.
+//
+// It panics if ctor is not a non-variadic function with such results.
+func ProvideFn[T any](dic Container, name string, ctor any) {
+	fv := reflect.ValueOf(ctor)
+	if fv.Kind() != reflect.Func || fv.IsNil() {
+		panic(fmt.Sprintf("Cannot provide %q from %T: not a function", name, ctor))
+	}
+	ft := fv.Type()
+	target := reflect.TypeOf((*T)(nil)).Elem()
+	switch n := ft.NumOut(); {
+	case ft.IsVariadic():
+		panic(fmt.Sprintf("Cannot provide %q from %s: variadic functions are not supported", name, ft))
+	case n < 1 || n > 2 || n == 2 && ft.Out(1) != errorType || !ft.Out(0).AssignableTo(target):
+		panic(fmt.Sprintf("Cannot provide %q from %s: results must be a %s, optionally followed by an error", name, ft, target))
+	}
+	dic.Register(name, func(dic Container) (any, error) {
+		c := baseOf(dic)
+		args := make([]reflect.Value, ft.NumIn())
+		for i := range args {
+			t := ft.In(i)
+			arg, err := c.resolveType(typeKey{t: t})
+			if err != nil {
+				return nil, fmt.Errorf("cannot resolve parameter %d of type %s: %w", i, t, err)
+			}
+			if args[i] = reflect.ValueOf(arg); !args[i].IsValid() { // nil instance
+				args[i] = reflect.Zero(t)
+			}
+		}
+		results := fv.Call(args)
+		if len(results) == 2 && !results[1].IsNil() {
+			return nil, results[1].Interface().(error)
+		}
+		instance, _ := results[0].Interface().(T) // Assignable, unless nil.
+		return instance, nil
+	})
+}
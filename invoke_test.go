@@ -0,0 +1,133 @@
+package izidic_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+type server struct {
+	cfg     *config
+	plugins []string
+}
+
+func TestInvoke(t *testing.T) {
+	dic := izidic.New()
+	izidic.ProvideType(dic, func(izidic.Container) (*config, error) {
+		return &config{prefix: "app"}, nil
+	})
+
+	// Value result, stored as a type-keyed service.
+	err := izidic.Invoke(dic, func(cfg *config, plugins ...string) *server {
+		return &server{cfg: cfg, plugins: plugins}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	srv, err := izidic.Resolve[*server](dic)
+	if err != nil || srv.cfg.prefix != "app" || len(srv.plugins) != 0 {
+		t.Fatalf("got %#v, %v, but expected a server without plugins", srv, err)
+	}
+
+	// Variadic parameter provided as a slice.
+	izidic.ProvideType(dic, func(izidic.Container) ([]string, error) {
+		return []string{"a", "b"}, nil
+	})
+	var plugins []string
+	err = izidic.Invoke(dic, func(p ...string) { plugins = p })
+	if err != nil || strings.Join(plugins, ",") != "a,b" {
+		t.Fatalf("got %v, %v, but expected plugins a,b", plugins, err)
+	}
+
+	// Error results.
+	errFailed := errors.New("failed")
+	if err := izidic.Invoke(dic, func(*server) error { return errFailed }); err != errFailed {
+		t.Fatalf("got error %v, but expected %v", err, errFailed)
+	}
+	if err := izidic.Invoke(dic, func() (int, error) { return 0, errFailed }); err != errFailed {
+		t.Fatalf("got error %v, but expected %v", err, errFailed)
+	}
+	if _, err := izidic.Resolve[int](dic); err == nil {
+		t.Fatal("failed invocation stored its result")
+	}
+
+	tests := [...]struct {
+		name     string
+		fn       any
+		expected string
+	}{
+		{"not func", 42, "cannot invoke int: not a function"},
+		{"bad results", func() (int, int) { return 0, 0 }, "cannot invoke func() (int, int): results must be a value, an error, or both"},
+		{"unresolved", func(int) {}, "cannot resolve parameter 0 of type int: no provider for type int"},
+		{"duplicate", func() *config { return nil }, "cannot store result: type *izidic_test.config is already provided"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := izidic.Invoke(dic, test.fn); fmt.Sprint(err) != test.expected {
+				t.Fatalf("got error %v, but expected %q", err, test.expected)
+			}
+		})
+	}
+
+	dic.Freeze()
+	err = izidic.Invoke(dic, func() float64 { return 1 })
+	if expected := "cannot store result of type float64 on frozen container"; fmt.Sprint(err) != expected {
+		t.Fatalf("got error %v, but expected %q", err, expected)
+	}
+}
+
+func TestInvoke_VariadicError(t *testing.T) {
+	errBoom := errors.New("boom")
+	dic := izidic.New()
+	izidic.ProvideType(dic, func(izidic.Container) ([]string, error) {
+		return nil, errBoom
+	})
+	called := false
+	err := izidic.Invoke(dic, func(...string) { called = true })
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("got error %v, but expected it to wrap %v", err, errBoom)
+	}
+	if called {
+		t.Fatal("fn was called despite the failed variadic provider")
+	}
+}
+
+func TestProvideFn(t *testing.T) {
+	errFailed := errors.New("failed")
+	dic := izidic.New()
+	izidic.ProvideType(dic, func(izidic.Container) (*config, error) { return &config{prefix: "app: "}, nil })
+	izidic.ProvideType(dic, func(izidic.Container) (string, error) { return "demo", nil })
+	izidic.ProvideFn[fmt.Stringer](dic, "app", func(cfg *config, name string) *strings.Builder {
+		b := &strings.Builder{}
+		b.WriteString(cfg.prefix + name)
+		return b
+	})
+	izidic.ProvideFn[int](dic, "failing", func() (int, error) { return 0, errFailed })
+	izidic.ProvideFn[int](dic, "unresolved", func(float64) int { return 0 })
+	dic.Freeze()
+
+	if actual := dic.MustService("app").(fmt.Stringer).String(); actual != "app: demo" {
+		t.Fatalf("got %q, but expected %q", actual, "app: demo")
+	}
+	if _, err := dic.Service("failing"); !errors.Is(err, errFailed) {
+		t.Fatalf("got error %v, but expected %v", err, errFailed)
+	}
+	const expected = "cannot resolve parameter 0 of type float64: no provider for type float64"
+	if _, err := dic.Service("unresolved"); err == nil || !strings.Contains(err.Error(), expected) {
+		t.Fatalf("got error %v, but expected it to contain %q", err, expected)
+	}
+
+	for _, ctor := range []any{42, func(...int) int { return 0 }, func() string { return "" }, func() (int, int) { return 0, 0 }} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected providing from %T to panic", ctor)
+				}
+			}()
+			izidic.ProvideFn[int](izidic.New(), "bad", ctor)
+		}()
+	}
+}
@@ -4,18 +4,28 @@
 // allowing users to store definitions of services requiring other services
 // before those are actually defined.
 //
-// Container writes are not concurrency-safe, so they are locked with Container.Freeze()
-// after the initial setup, which is assumed to be non-concurrent.
+// Container writes are only allowed during the initial setup, after which they are
+// locked with Container.Freeze(). All accesses to the container maps are locked,
+// so introspection methods like Container.Names() are safe to call at any time.
 package izidic
 
 import (
+	"context"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"log/slog"
 	"runtime"
+	"runtime/trace"
 	"sort"
 	"sync"
+	"time"
 )
 
+// ErrCircularDependency is the error wrapped by errors reporting a dependency cycle.
+var ErrCircularDependency = errors.New("circular dependency detected")
+
 // Service is the type used to define container serviceDefs accessors.
 //
 // It takes an instance of the container and returns an instance of the desired service,
@@ -26,35 +36,218 @@ type Service func(dic Container) (any, error)
 
 // Container represents any implementation of a dependency injection container.
 type Container interface {
+	AfterResolve(name string, fn func(instance any, err error))
+	AliasParam(alias, target string) error
+	BeforeResolve(name string, fn func())
+	CheckNamespaceCollisions() error
+	Checkpoint() func()
+	Clock() Clock
+	CloneShared() Container
+	Close() error
+	CreationStack(name string) []runtime.Frame
+	DeclareParam(name string, spec ParamSpec)
+	DependencyMatrix() map[string]map[string]bool
+	DependsOnParam(serviceName string, paramNames ...string)
+	Explain(name string) (Explanation, error)
+	Fingerprint() string
 	Freeze()
+	FreezeOrError() error
+	FreezeWithAnalysis() error
+	InstantiationManifest() []ManifestEntry
+	Into(name string, target any) error
+	Inventory() []Entry
+	Invalidate(name string)
+	MarkSecret(name string)
+	Meta(name string) map[string]any
+	MustBeReady()
 	MustParam(name string) any
+	MustParamN(name Name) any
 	MustService(name string) any
+	MustServiceN(name Name) any
 	Names() map[string][]string
+	NamesCached() map[string][]string
 	Param(name string) (any, error)
+	ParamEnum(name string) (string, error)
+	ParamN(name Name) (any, error)
+	ParamReport() []ParamInfo
+	ParamsByTag(tag string) map[string]any
+	Rebuild(name string) (any, error)
 	Register(name string, fn Service)
+	RegisterModule(name string, imports []string, register func(Container))
+	RegisterN(name Name, fn Service)
+	RegisterNilable(name string, fn Service)
+	RegisterProfile(profile, name string, fn Service)
+	RegisterCached(name string, fn Service, ttl time.Duration)
+	RegisterLogged(name string, fn LoggedService)
+	RegisterWithMeta(name string, meta map[string]any, fn Service)
+	RegisterVersioned(name, version string, fn Service)
+	RegisterWithRetry(name string, fn Service, attempts int, backoff time.Duration)
+	RegisterWithTimeout(name string, fn Service, d time.Duration)
+	Remove(name string, since string)
+	ResetStats()
 	Store(name string, param any)
+	StoreEnum(name string, value string, allowed []string) error
+	StoreFromFlagSet(fs *flag.FlagSet)
+	StoreFromService(paramName, serviceName string)
+	StoreN(name Name, param any)
+	StoreSecret(name string, v any)
+	StoreTagged(name string, tags []string, v any)
+	Seal() Container
 	Service(name string) (any, error)
+	ServiceN(name Name) (any, error)
+	Services(names ...string) ([]any, error)
+	ServiceVersion(name, version string) (any, error)
+	ServiceWith(name string, overrides map[string]any) (any, error)
+	ServiceWithParams(name string, params map[string]any) (any, error)
+	SetProfile(name string)
+	SetRegisterInterceptor(interceptor func(name string, fn Service) Service)
+	State() State
+	Stats() Stats
+	Swap(build func(Container)) error
+	Unused() []string
+	Update(name string, v any) error
+	Validate() error
+	WithContext(ctx context.Context) Container
+	WarmupAll() map[string]error
+	WarmupParallel(ctx context.Context) error
+	WriteFlameGraph(w io.Writer) error
 }
 
 // container is the container, holding both parameters and services
 type container struct {
-	sync.RWMutex // Lock for service instances
-	frozen       bool
-	parameters   map[string]any
-	serviceDefs  map[string]Service
-	services     map[string]any
+	sync.RWMutex                                             // Lock for service instances
+	acquired      []acquisition                              // Set by AcquireT, closed by Close
+	after         map[string][]func(instance any, err error) // Set by AfterResolve
+	analyzed      bool                                       // Set by FreezeWithAnalysis
+	before        map[string][]func()                        // Set by BeforeResolve
+	budget        time.Duration                              // Set by WithResolutionBudget
+	budgetSpent   time.Duration                              // Exclusive time spent in factories, under a budget
+	budgetTripper string                                     // The service whose instantiation exceeded the budget
+	built         map[string]time.Time                       // Instantiation time of services registered by RegisterCached
+	clock         Clock
+	closeOnSwap   bool                       // Set by WithCloseOnSwap
+	edges         map[string][]string        // Dependencies of each service, recorded by FreezeWithAnalysis
+	enums         map[string][]string        // Allowed values of parameters stored by StoreEnum
+	flights       map[string]*flight         // Instantiations in progress, by key
+	generation    uint64                     // Incremented by Swap
+	imports       map[string]map[string]bool // Modules imported by each module
+	interceptor   func(name string, fn Service) Service
+	logger        *slog.Logger               // Set by WithLogger
+	lockMetrics   func(waited time.Duration) // Set by WithLockMetrics
+	lockThreshold time.Duration
+	manifest      []ManifestEntry // First instantiations, in order
+	manifested    map[string]bool // Services in the manifest
+	meta          map[string]map[string]any
+	module        string              // The module being registered by RegisterModule
+	mustHandler   func(error)         // Set by WithMustAsError
+	names         map[string][]string // Snapshot returned by NamesCached, reset when definitions change
+	nilable       map[string]bool     // Services registered by RegisterNilable
+	normalizer    func(string) string // Set by WithNameNormalizer
+	opaque        bool                // Set by WithIntrospection(false)
+	overrides     map[string]bool     // Services defined for the active profile
+	owned         []acquisition       // io.Closer instances built by a scope from WithContext, in order
+	owners        map[string]string   // Modules owning services
+	paramAliases  map[string]string   // Parameter aliases and their targets
+	paramServices map[string]string   // Services providing parameters stored by StoreFromService
+	paramTags     map[string][]string // Tags of parameters stored by StoreTagged
+	parameters    map[string]any
+	profile       string              // Set by SetProfile
+	profiles      map[string][]string // Profiles of the services registered by RegisterProfile
+	removed       map[string]string   // Versions in which names were removed, set by Remove
+	required      []string            // Services which must have been resolved by Close
+	scoped        bool                // Set on scopes returned by WithContext
+	secrets       map[string]bool     // Parameters redacted in reports
+	seeds         []func()            // Definitions from options, applied by New after all options
+	serviceDefs   map[string]Service
+	services      map[string]any
+	sharedDefs    bool // Set by CloneShared until serviceDefs is copied
+	sharedParams  bool // Set by CloneShared until parameters is copied
+	specs         map[string]ParamSpec
+	stacks        map[string][]runtime.Frame // Set by WithCreationStacks
+	strict        bool                       // Set by WithStrictResolution
+	state         State
+	stats         counters
+	timeout       time.Duration // Set by WithDefaultTimeout
+	traced        bool
+	ttls          map[string]time.Duration // Cache duration of services registered by RegisterCached
+	warmup        int                      // Set by WithWarmupConcurrency
+	watchers      map[string][]string      // Services invalidated by Update of each parameter, set by DependsOnParam
+
+	typed          map[typeKey]func(Container) (any, error)
+	typedInstances map[typeKey]any
+	typedOrder     []typeKey // Type-keyed services, in provision order
+
+	versions         map[string]map[string]versionedService
+	versionInstances map[string]any
 }
 
 // Freeze converts the container from build mode, which does not support
 // concurrency, to run mode, which does.
+//
+// Freezing a frozen container does nothing, but freezing a closed one panics.
+// It also panics with the Validate error if declared parameters are invalid.
 func (dic *container) Freeze() {
-	dic.frozen = true
+	if err := dic.Validate(); err != nil {
+		panic(err)
+	}
+	dic.Lock()
+	defer dic.Unlock()
+	if dic.state == Closed {
+		panic("Cannot freeze closed container")
+	}
+	dic.state = Frozen
+}
+
+// Seal freezes the container like Freeze, and returns it, so that composition
+// roots can end with a single statement:
+//
+//	return dic.Seal()
+//
+// When called through a type embedding the container, it returns the embedded
+// container, not the embedding one.
+func (dic *container) Seal() Container {
+	dic.Freeze()
+	return dic
+}
+
+// FreezeOrError freezes the container only if it passes all the checks available
+// without instantiating services in it, as a safe end-of-wiring gate:
+//
+//  1. Validate, checking declared parameters
+//  2. CheckNamespaceCollisions, checking for names both parameters and services
+//  3. CheckCycles, checking for dependency cycles in a throwaway copy
+//
+// All checks run, and their errors are joined in that order. On error, the
+// container remains in the Building state, so that issues can be fixed.
+// Unlike FreezeWithAnalysis, it does not record dependencies, so later
+// resolutions still check for cycles.
+func (dic *container) FreezeOrError() error {
+	if err := errors.Join(dic.Validate(), dic.CheckNamespaceCollisions(), CheckCycles(dic)); err != nil {
+		return err
+	}
+	dic.Lock()
+	defer dic.Unlock()
+	if dic.state == Closed {
+		return errors.New("cannot freeze closed container")
+	}
+	dic.state = Frozen
+	return nil
+}
+
+// Meta returns a copy of the metadata attached to a service by RegisterWithMeta,
+// or nil if the service has no metadata.
+func (dic *container) Meta(name string) map[string]any {
+	name = dic.normalize(name)
+	dic.RLock()
+	defer dic.RUnlock()
+	return copyMap(dic.meta[name])
 }
 
 func (dic *container) MustParam(name string) any {
 	p, err := dic.Param(name)
 	if err != nil {
-		panic(err)
+		dic.must(err)
+		return nil
 	}
 	return p
 }
@@ -62,22 +255,32 @@ func (dic *container) MustParam(name string) any {
 func (dic *container) MustService(name string) any {
 	instance, err := dic.Service(name)
 	if err != nil {
-		panic(err)
+		dic.must(err)
+		return nil
 	}
 	return instance
 }
 
-// Names returns the names of all the parameters and instances defined on the container.
+// Names returns the names of all the parameters and instances defined on the container,
+// keyed by section: "params", "paramAliases", and "services".
 func (dic *container) Names() map[string][]string {
-	dump := map[string][]string{
-		"params":   make([]string, 0, len(dic.parameters)),
-		"services": make([]string, 0, len(dic.serviceDefs)),
-	}
 	dic.RLock()
 	defer dic.RUnlock()
+	dump := map[string][]string{
+		"paramAliases": make([]string, 0, len(dic.paramAliases)),
+		"params":       make([]string, 0, len(dic.parameters)),
+		"services":     make([]string, 0, len(dic.serviceDefs)),
+	}
+	for k := range dic.paramAliases {
+		dump["paramAliases"] = append(dump["paramAliases"], k)
+	}
+	sort.Strings(dump["paramAliases"])
 	for k := range dic.parameters {
 		dump["params"] = append(dump["params"], k)
 	}
+	for k := range dic.paramServices {
+		dump["params"] = append(dump["params"], k)
+	}
 	sort.Strings(dump["params"])
 	for k := range dic.serviceDefs {
 		dump["services"] = append(dump["services"], k)
@@ -86,48 +289,511 @@ func (dic *container) Names() map[string][]string {
 	return dump
 }
 
+// NamesCached returns the same names as Names, but as a snapshot shared by all
+// callers, only recomputed after definitions change. On a frozen container, it
+// never changes, so calls after the first one do not allocate, which suits
+// frequent polling like a debug endpoint.
+//
+// The returned map and slices must not be modified.
+func (dic *container) NamesCached() map[string][]string {
+	dic.RLock()
+	names := dic.names
+	dic.RUnlock()
+	if names != nil {
+		return names
+	}
+
+	names = dic.Names()
+	dic.Lock()
+	defer dic.Unlock()
+	// Concurrent first calls on a frozen container build identical snapshots:
+	// keep the first one, so that all callers share it.
+	if dic.names == nil {
+		dic.names = names
+	}
+	return dic.names
+}
+
 func (dic *container) Param(name string) (any, error) {
+	name = dic.normalize(name)
+	if service, derived := dic.paramService(name); derived {
+		instance, err := dic.Service(service)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %q from service %q: %w", name, service, err)
+		}
+		return instance, nil
+	}
 	dic.RLock()
 	defer dic.RUnlock()
+	if err := dic.checkOpen("parameter", name); err != nil {
+		return nil, err
+	}
 
-	p, found := dic.parameters[name]
-	if !found {
-		return nil, fmt.Errorf("parameter not found: %q", name)
+	target := dic.paramTarget(name)
+	p, found := dic.parameters[target]
+	switch {
+	case !found && target != name:
+		return nil, fmt.Errorf("parameter alias %q: %w", name, dic.notFound("parameter", target))
+	case !found:
+		return nil, dic.notFound("parameter", name)
 	}
 	return p, nil
 }
 
+// Unused returns the sorted names of the services registered on the container
+// which have never been instantiated.
+//
+// Services only used on some code paths are expected to be listed if these
+// paths did not run, so this is a hint for pruning definitions, not a proof.
+func (dic *container) Unused() []string {
+	dic.RLock()
+	defer dic.RUnlock()
+	var unused []string
+	for name := range dic.serviceDefs {
+		if _, found := dic.services[name]; !found {
+			unused = append(unused, name)
+		}
+	}
+	sort.Strings(unused)
+	return unused
+}
+
 // Register registers a service with the container.
 func (dic *container) Register(name string, fn Service) {
-	if dic.frozen {
-		panic("Cannot register services on frozen container")
+	name = dic.normalize(name)
+	dic.register(name, fn, nil)
+}
+
+// RegisterWithMeta registers a service with the container, attaching metadata
+// like documentation, owner, or version, for introspection purposes.
+//
+// Metadata has no effect on resolution.
+func (dic *container) RegisterWithMeta(name string, meta map[string]any, fn Service) {
+	name = dic.normalize(name)
+	dic.register(name, fn, copyMap(meta))
+}
+
+// register defines a service, bounded by the default timeout, if any, and
+// reports whether it did, like define.
+func (dic *container) register(name string, fn Service, meta map[string]any) bool {
+	dic.RLock()
+	timeout := dic.timeout
+	dic.RUnlock()
+	return dic.define(name, fn, meta, timeout)
+}
+
+// define defines a service, bounded by timeout if it is positive, and reports
+// whether it did, or discarded the definition for one of the active profile.
+func (dic *container) define(name string, fn Service, meta map[string]any, timeout time.Duration) bool {
+	mustFactory(name, fn)
+	// The interceptor is user code, so it must not run while holding the lock.
+	dic.RLock()
+	interceptor := dic.interceptor
+	dic.RUnlock()
+	if interceptor != nil {
+		fn = interceptor(name, fn)
+	}
+	fn = withTimeout(fn, timeout)
+
+	dic.Lock()
+	defer dic.Unlock()
+	dic.mustBuild("register services")
+	if dic.overrides[name] {
+		return false // A definition for the active profile replaces the default.
+	}
+	dic.ownDefs()
+	dic.serviceDefs[name] = dic.own(name, fn)
+	dic.names = nil
+	delete(dic.versions, name)
+	delete(dic.ttls, name)
+	delete(dic.nilable, name)
+	if meta == nil {
+		delete(dic.meta, name)
+		return true
+	}
+	dic.meta[name] = meta
+	return true
+}
+
+// mustFactory panics if a service factory is nil, which would otherwise only
+// fail when resolving the service.
+func mustFactory(name string, fn Service) {
+	if fn == nil {
+		panic(fmt.Sprintf("cannot register nil factory for %q", name))
 	}
-	dic.serviceDefs[name] = fn
 }
 
 // Service returns the single instance of the requested service on success.
+//
+// Concurrent first resolutions of a service run its factory once, the other
+// callers waiting for its instance. Factories must therefore request their
+// dependencies from the Container they receive, which reports cycles, and not
+// from the container itself, on which requesting a service being built blocks.
 func (dic *container) Service(name string) (any, error) {
+	return dic.service(dic.normalize(name), nil)
+}
+
+// service resolves a service requested by the factory of the last service in
+// chain, if any, chain holding the services being resolved, outermost first.
+func (dic *container) service(name string, chain []string) (instance any, err error) {
+	dic.stats.resolutions.Add(1)
+	defer func() {
+		if err != nil {
+			dic.stats.errors.Add(1)
+		}
+	}()
+	if dic.strict {
+		defer recoverStrict(name, &err)
+	}
 	// Reuse existing instance if any.
 	dic.RLock()
-	instance, found := dic.services[name]
+	instance, found := dic.instance(name)
+	service, defined := dic.serviceDefs[name]
+	generation := dic.generation
+	_, captured := dic.stacks[name]
+	capture := dic.stacks != nil && !captured && !dic.opaque
+	err = dic.checkOpen("service", name)
 	dic.RUnlock()
+	if err != nil {
+		return nil, err
+	}
 	if found {
+		dic.stats.hits.Add(1)
 		return instance, nil
 	}
 
-	// Otherwise instantiate. No lock because no concurrent writes can happen:
-	// - during build, recursive calls may happen, but not concurrently
-	// - after freeze, no new services may be created: see container.Register
-	service, found := dic.serviceDefs[name]
-	if !found {
-		return nil, fmt.Errorf("service not found: %q", name)
+	// Otherwise instantiate, without holding the lock since the service may
+	// recursively request other services.
+	if !defined {
+		dic.RLock()
+		defer dic.RUnlock()
+		return nil, dic.notFound("service", name)
+	}
+
+	var stack []runtime.Frame
+	if capture {
+		stack = callers()
+	}
+
+	f, lead, err := dic.join(name, chain, func() (any, bool) { return dic.instance(name) })
+	switch {
+	case err != nil:
+		return nil, err
+	case !lead:
+		dic.stats.hits.Add(1)
+		return dic.wait(f, chain)
+	}
+	defer dic.land(name, f)
+	f.instance, f.err = dic.instantiate(name, service, chain, generation, stack)
+	return f.instance, f.err
+}
+
+// instantiate builds a service for the flight led by service, and caches it
+// unless Swap replaced the definitions since generation.
+func (dic *container) instantiate(name string, service Service, chain []string, generation uint64, stack []runtime.Frame) (any, error) {
+	instance, view, elapsed, err := dic.build(name, name, service, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	if dic.lockMetrics == nil {
+		dic.Lock()
+	} else {
+		start := time.Now()
+		dic.Lock()
+		if waited := time.Since(start); waited >= dic.lockThreshold {
+			// Deferred calls run in reverse order, so this runs after unlocking.
+			defer dic.lockMetrics(waited)
+		}
+	}
+	defer dic.Unlock()
+	if dic.generation != generation {
+		return instance, nil // Built from definitions replaced by Swap: do not cache it.
+	}
+	// Rebuild may have instantiated the service meanwhile: keep the first
+	// instance so that all callers share it.
+	if existing, found := dic.instance(name); found {
+		return existing, nil
+	}
+	dic.services[name] = instance
+	dic.ownInstance(name, instance)
+	if _, cached := dic.ttls[name]; cached {
+		dic.built[name] = dic.clock.Now()
+	}
+	if _, captured := dic.stacks[name]; stack != nil && !captured {
+		dic.stacks[name] = stack
+	}
+	dic.record(name, elapsed, view)
+
+	return instance, nil
+}
+
+// build runs the factory of a service, or of a version of it when key differs
+// from name, with cycle detection, the resolution budget, the resolution hooks
+// of the service, and the nil check, returning the view passed to the factory
+// and the time spent in it, for the caller to cache the instance.
+func (dic *container) build(name, key string, service Service, chain []string) (instance any, view *factoryView, elapsed time.Duration, err error) {
+	dic.RLock()
+	defs := len(dic.serviceDefs)
+	for _, versions := range dic.versions {
+		defs += len(versions)
+	}
+	analyzed := dic.analyzed
+	before, after := dic.before[name], dic.after[name]
+	dic.RUnlock()
+
+	// A frozen graph proven acyclic by FreezeWithAnalysis needs no loop detection.
+	if !analyzed {
+		if err := checkCycle("github.com/fgm/izidic.(*container).build", defs); err != nil {
+			return nil, nil, 0, err
+		}
+	}
+
+	if dic.budget > 0 {
+		if err := dic.checkBudget(key); err != nil {
+			return nil, nil, 0, err
+		}
+	}
+
+	for _, hook := range before {
+		hook()
+	}
+	view = dic.factoryContainer(key, chain)
+	start := time.Now()
+	if dic.traced {
+		trace.WithRegion(context.Background(), "izidic:"+key, func() {
+			instance, err = service(view)
+		})
+	} else {
+		instance, err = service(view)
+	}
+	elapsed = time.Since(start)
+	if dic.budget > 0 {
+		dic.spend(key, elapsed-time.Duration(view.nested.Load()))
+	}
+	for _, hook := range after {
+		hook(instance, err)
+	}
+	if err == nil {
+		err = dic.checkNil(name, instance)
+	}
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("failed instantiating service %s: %w", key, err)
+	}
+	dic.stats.instantiations.Add(1)
+	return instance, view, elapsed, nil
+}
+
+// Rebuild runs the factory of a service again, and replaces its cached instance
+// with the new one, which it returns, e.g. to reload credentials.
+//
+// Unlike Invalidate, it does not affect dependents: services built before with
+// the previous instance keep it until they are rebuilt themselves, and so does
+// any caller holding it. To have existing holders see the refresh, make the
+// service itself hold its state behind an atomic pointer, updated on Rebuild.
+//
+// The factory runs like on a first resolution, with the resolution budget,
+// hooks, and execution trace, and counts as an instantiation in Stats.
+// On error, the cached instance, if any, is kept.
+func (dic *container) Rebuild(name string) (instance any, err error) {
+	name = dic.normalize(name)
+	if dic.strict {
+		defer recoverStrict(name, &err)
+	}
+	dic.RLock()
+	service, defined := dic.serviceDefs[name]
+	generation := dic.generation
+	err = dic.checkOpen("service", name)
+	if err == nil && !defined {
+		err = dic.notFound("service", name)
+	}
+	dic.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	instance, view, elapsed, err := dic.build(name, name, service, nil)
+	if err != nil {
+		return nil, err
+	}
+	dic.Lock()
+	defer dic.Unlock()
+	if dic.generation != generation {
+		return instance, nil // Built from definitions replaced by Swap: do not cache it.
+	}
+	dic.services[name] = instance
+	dic.ownInstance(name, instance)
+	if _, cached := dic.ttls[name]; cached {
+		dic.built[name] = dic.clock.Now()
+	}
+	dic.record(name, elapsed, view)
+	return instance, nil
+}
+
+// Services resolves several services in order, returning their instances in the
+// same order, so a composition root can index them, like:
+//
+//	instances, err := dic.Services("logger", "db")
+//	if err != nil {
+//		return err
+//	}
+//	logger, db := instances[0].(*log.Logger), instances[1].(*sql.DB)
+//
+// Since instance types differ, callers still need to type-assert each instance.
+// Resolution stops at the first error, which names the failing service.
+func (dic *container) Services(names ...string) ([]any, error) {
+	instances := make([]any, len(names))
+	for i, name := range names {
+		instance, err := dic.Service(name)
+		if err != nil {
+			return nil, fmt.Errorf("resolving service %d %q: %w", i, name, err)
+		}
+		instances[i] = instance
+	}
+	return instances, nil
+}
+
+// ServiceWith resolves a service in a temporary child container where the overrides
+// are pre-seeded as service instances, replacing the definitions of these services.
+//
+// The child shares the parameters and definitions of the container, but not its
+// instances: all services needed for the resolution, apart from the overrides,
+// are instantiated afresh and discarded afterwards.
+// The container itself and its instances are left untouched.
+func (dic *container) ServiceWith(name string, overrides map[string]any) (any, error) {
+	name = dic.normalize(name)
+	dic.RLock()
+	err := dic.checkOpen("service", name)
+	child := dic.child()
+	dic.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	now := child.clock.Now()
+	for k, v := range overrides {
+		k = dic.normalize(k)
+		child.services[k] = v
+		if _, cached := child.ttls[k]; cached {
+			child.built[k] = now // Overrides of cached services must not count as expired.
+		}
 	}
+	return child.Service(name)
+}
 
-	// Loop detection: if the call stack contains more calls to Service reaching
-	// this step than there are services defined in the container, then resolution
-	// for at least one service was attempted more than once, which implies a
-	// dependency cycle.
-	const funcName = "github.com/fgm/izidic.(*container).Service"
+// ServiceWithParams resolves a service in a temporary child container where the
+// given parameters shadow those of the container, like ServiceWith does for
+// services, e.g. to build the same service with different timeouts in tests.
+//
+// All services needed for the resolution are instantiated afresh in the child,
+// so those reading the shadowed parameters see the given values, and they are
+// discarded afterwards. The container itself and its instances are left untouched.
+func (dic *container) ServiceWithParams(name string, params map[string]any) (any, error) {
+	name = dic.normalize(name)
+	dic.RLock()
+	err := dic.checkOpen("service", name)
+	child := dic.child()
+	dic.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	child.paramAliases, child.paramServices = copyMap(child.paramAliases), copyMap(child.paramServices)
+	for k, v := range params {
+		k = dic.normalize(k)
+		child.parameters[k] = v
+		delete(child.paramAliases, k)
+		delete(child.paramServices, k)
+	}
+	return child.Service(name)
+}
+
+// child returns a frozen container with copies of the definitions and parameters
+// of the container, but none of its instances, for one-off resolutions.
+//
+// Callers must hold the lock.
+func (dic *container) child() *container {
+	return &container{
+		state:         Frozen,
+		after:         dic.after,
+		analyzed:      dic.analyzed,
+		before:        dic.before,
+		budget:        dic.budget,
+		strict:        dic.strict,
+		clock:         dic.clock,
+		traced:        dic.traced,
+		edges:         dic.edges,
+		enums:         dic.enums,
+		imports:       dic.imports,
+		lockMetrics:   dic.lockMetrics,
+		lockThreshold: dic.lockThreshold,
+		logger:        dic.logger,
+		manifested:    make(map[string]bool),
+		meta:          dic.meta,
+		mustHandler:   dic.mustHandler,
+		nilable:       dic.nilable,
+		normalizer:    dic.normalizer,
+		opaque:        dic.opaque,
+		overrides:     dic.overrides,
+		owners:        dic.owners,
+		paramAliases:  dic.paramAliases,
+		paramServices: dic.paramServices,
+		paramTags:     dic.paramTags,
+		parameters:    copyMap(dic.parameters),
+		profile:       dic.profile,
+		profiles:      dic.profiles,
+		removed:       dic.removed,
+		secrets:       dic.secrets,
+		serviceDefs:   copyMap(dic.serviceDefs),
+		services:      make(map[string]any),
+		specs:         dic.specs,
+		timeout:       dic.timeout,
+		ttls:          copyMap(dic.ttls),
+		built:         make(map[string]time.Time),
+		warmup:        dic.warmup,
+		watchers:      dic.watchers,
+
+		versions:         copyMap(dic.versions),
+		versionInstances: make(map[string]any),
+
+		typed:          copyMap(dic.typed),
+		typedInstances: make(map[typeKey]any),
+		typedOrder:     dic.typedOrder,
+	}
+}
+
+// SetRegisterInterceptor sets a function through which all subsequent Register
+// calls pass their service before storing it, allowing it to wrap the service,
+// e.g. for tracing or timing, without changing each registration.
+//
+// Services registered before the call are not affected.
+// Only one interceptor is active at a time: setting a new one replaces the
+// previous one, and setting nil removes it.
+func (dic *container) SetRegisterInterceptor(interceptor func(name string, fn Service) Service) {
+	dic.Lock()
+	defer dic.Unlock()
+	dic.mustBuild("set register interceptor")
+	dic.interceptor = interceptor
+}
+
+// Store stores a parameter in the container.
+func (dic *container) Store(name string, param any) {
+	name = dic.normalize(name)
+	dic.Lock()
+	defer dic.Unlock()
+	dic.mustBuild("store parameters")
+	dic.mustEnum(name, param)
+	dic.ownParams()
+	dic.parameters[name] = param
+	delete(dic.paramAliases, name)
+	delete(dic.paramServices, name)
+	dic.names = nil
+}
+
+// checkCycle detects dependency cycles during resolution.
+//
+// If the call stack contains more calls to the resolving function funcName than
+// there are definitions in the container, then resolution for at least one of
+// them was attempted more than once, which implies a dependency cycle.
+func checkCycle(funcName string, defs int) error {
 	// We need a vastly oversized value to cover the case of deeply nested dic.Service() calls.
 	pcs := make([]uintptr, 1e6)
 	n := runtime.Callers(1, pcs)
@@ -143,36 +809,103 @@ func (dic *container) Service(name string) (any, error) {
 			break
 		}
 	}
-	if serviceCalls > len(dic.serviceDefs) {
-		return nil, errors.New("circular dependency detected")
+	if serviceCalls > defs {
+		return ErrCircularDependency
 	}
+	return nil
+}
 
-	instance, err := service(dic)
-	if err != nil {
-		return nil, fmt.Errorf("failed instantiating service %s: %w", name, err)
+// notFound builds the error for a missing parameter or service, hinting at the
+// other namespace when the name is defined there, or naming the version in
+// which it was removed, as marked by Remove.
+//
+// Callers must hold the lock.
+func (dic *container) notFound(kind, name string) error {
+	if since, removed := dic.removed[name]; removed {
+		return fmt.Errorf("%q was removed in %s; see migration notes", name, since)
 	}
+	switch kind {
+	case "parameter":
+		if _, ok := dic.serviceDefs[name]; ok {
+			return fmt.Errorf("parameter not found: %q is a service, not a parameter; use Service()", name)
+		}
+	case "service":
+		if _, ok := dic.parameters[name]; ok {
+			return fmt.Errorf("service not found: %q is a parameter, not a service; use Param()", name)
+		}
+		if err := dic.profileNotFound(name); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("%s not found: %q", kind, name)
+}
 
-	dic.Lock()
-	defer dic.Unlock()
-	dic.services[name] = instance
-
-	return instance, nil
+// must handles the error of a Must method, by panicking with it, or passing it
+// to the handler set by WithMustAsError.
+func (dic *container) must(err error) {
+	if dic.mustHandler != nil {
+		dic.mustHandler(err)
+		return
+	}
+	panic(err)
 }
 
-// Store stores a parameter in the container.
-func (dic *container) Store(name string, param any) {
-	if dic.frozen {
-		panic("Cannot store parameters on frozen container")
+// copyMap returns a shallow copy of a map, or nil for a nil map.
+func copyMap[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return nil
 	}
-	dic.parameters[name] = param
+	res := make(map[K]V, len(m))
+	for k, v := range m {
+		res[k] = v
+	}
+	return res
 }
 
-// New creates a container ready for use.
-func New() Container {
-	return &container{
-		RWMutex:     sync.RWMutex{},
-		parameters:  make(map[string]any),
-		serviceDefs: make(map[string]Service),
-		services:    make(map[string]any),
+// New creates a container ready for use, applying the given options in order.
+func New(opts ...Option) Container {
+	dic := &container{
+		RWMutex:       sync.RWMutex{},
+		after:         make(map[string][]func(any, error)),
+		before:        make(map[string][]func()),
+		built:         make(map[string]time.Time),
+		clock:         realClock{},
+		enums:         make(map[string][]string),
+		imports:       make(map[string]map[string]bool),
+		manifested:    make(map[string]bool),
+		meta:          make(map[string]map[string]any),
+		nilable:       make(map[string]bool),
+		overrides:     make(map[string]bool),
+		owners:        make(map[string]string),
+		paramAliases:  make(map[string]string),
+		paramServices: make(map[string]string),
+		paramTags:     make(map[string][]string),
+		parameters:    make(map[string]any),
+		profiles:      make(map[string][]string),
+		removed:       make(map[string]string),
+		secrets:       make(map[string]bool),
+		serviceDefs:   make(map[string]Service),
+		services:      make(map[string]any),
+		specs:         make(map[string]ParamSpec),
+		ttls:          make(map[string]time.Duration),
+		watchers:      make(map[string][]string),
+
+		versions:         make(map[string]map[string]versionedService),
+		versionInstances: make(map[string]any),
+
+		typed:          make(map[typeKey]func(Container) (any, error)),
+		typedInstances: make(map[typeKey]any),
+	}
+	for _, opt := range opts {
+		opt(dic)
+	}
+	// Seed definitions once options like WithNameNormalizer are all applied.
+	for _, seed := range dic.seeds {
+		seed()
+	}
+	dic.seeds = nil
+	for i, name := range dic.required {
+		dic.required[i] = dic.normalize(name)
 	}
+	return dic
 }
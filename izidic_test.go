@@ -3,8 +3,12 @@ package izidic_test
 import (
 	"errors"
 	"fmt"
+	"math/rand"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/fgm/izidic"
 	"github.com/google/go-cmp/cmp"
@@ -177,21 +181,51 @@ func TestContainer_Names(t *testing.T) {
 
 	actual := dic.Names()
 	expected := map[string][]string{
-		"params":   {"p1", "p2"},
-		"services": {"s1", "s2"},
+		"paramAliases": {},
+		"params":       {"p1", "p2"},
+		"services":     {"s1", "s2"},
 	}
 	if !cmp.Equal(actual, expected) {
 		t.Logf("unequal results: %v", cmp.Diff(actual, expected))
 	}
 }
 
+func TestContainer_NamesCached(t *testing.T) {
+	dic := izidic.New()
+	dic.Store("p1", "v")
+	dic.Register("s1", s1)
+
+	first := dic.NamesCached()
+	if expected := dic.Names(); !cmp.Equal(first, expected) {
+		t.Fatalf("unexpected names: %s", cmp.Diff(first, expected))
+	}
+	if again := dic.NamesCached(); &again["services"][0] != &first["services"][0] {
+		t.Fatal("got a new snapshot, but expected the cached one")
+	}
+
+	dic.Register("s2", s2)
+	dic.Freeze()
+	actual := dic.NamesCached()
+	expected := map[string][]string{
+		"paramAliases": {},
+		"params":       {"p1"},
+		"services":     {"s1", "s2"},
+	}
+	if !cmp.Equal(actual, expected) {
+		t.Fatalf("unexpected names after registration: %s", cmp.Diff(actual, expected))
+	}
+	if allocs := testing.AllocsPerRun(10, func() { dic.NamesCached() }); allocs != 0 {
+		t.Fatalf("got %v allocations on a frozen container, but expected none", allocs)
+	}
+}
+
 func TestContainer_Freeze(t *testing.T) {
 	tests := [...]struct {
 		name     string
 		attempt  func(container izidic.Container)
 		expected string
 	}{
-		{"register", func(dic izidic.Container) { dic.Register("p", nil) }, "Cannot register services on frozen container"},
+		{"register", func(dic izidic.Container) { dic.Register("p", s1) }, "Cannot register services on frozen container"},
 		{"store", func(dic izidic.Container) { dic.Store("p", "v") }, "Cannot store parameters on frozen container"},
 	}
 	for _, test := range tests {
@@ -213,6 +247,52 @@ func TestContainer_Freeze(t *testing.T) {
 	}
 }
 
+func TestContainer_Register_Nil(t *testing.T) {
+	tests := [...]struct {
+		name     string
+		register func(dic izidic.Container)
+	}{
+		{"plain", func(dic izidic.Container) { dic.Register("p", nil) }},
+		{"retry", func(dic izidic.Container) { dic.RegisterWithRetry("p", nil, 2, 0) }},
+		{"versioned", func(dic izidic.Container) { dic.RegisterVersioned("p", "1.0.0", nil) }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				const expected = `cannot register nil factory for "p"`
+				if rec := recover(); fmt.Sprint(rec) != expected {
+					t.Fatalf("got panic %v, but expected %s", rec, expected)
+				}
+			}()
+			test.register(izidic.New())
+		})
+	}
+}
+
+func TestContainer_FreezeOrError(t *testing.T) {
+	dic := izidic.New()
+	dic.DeclareParam("port", izidic.ParamSpec{Required: true})
+	dic.Register("a", dependent("a", "b"))
+	dic.Register("b", dependent("b", "a"))
+	const expected = "parameter \"port\": required but not stored\n" +
+		"failed instantiating service a: failed instantiating service b: circular dependency detected: a -> b -> a"
+	if err := dic.FreezeOrError(); fmt.Sprint(err) != expected {
+		t.Fatalf("got error %v, but expected %s", err, expected)
+	}
+	if actual := dic.State(); actual != izidic.Building {
+		t.Fatalf("got state %s after failure, but expected %s", actual, izidic.Building)
+	}
+
+	dic.Store("port", 8080)
+	dic.Register("b", dependent("b"))
+	if err := dic.FreezeOrError(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual := dic.State(); actual != izidic.Frozen {
+		t.Fatalf("got state %s, but expected %s", actual, izidic.Frozen)
+	}
+}
+
 func TestContainer_Service_CircularDeps(t *testing.T) {
 	// We build a 3-level dependency because some simpler strategies to address 2-level (mutual) dependencies do not catch more complex ones,
 	sA := func(c izidic.Container) (any, error) {
@@ -248,3 +328,406 @@ func TestContainer_Service_CircularDeps(t *testing.T) {
 		t.Fatalf("got unexpected error: %#v", err)
 	}
 }
+
+func TestContainer_Service_SelfResolution(t *testing.T) {
+	dic := izidic.New()
+	dic.Register("x", dependent("x", "x"))
+	dic.Register("y", dependent("y", "x"))
+	dic.Freeze()
+
+	const expected = `failed instantiating service x: service "x" requested itself during instantiation`
+	for _, name := range []string{"x", "y"} {
+		_, err := dic.Service(name)
+		if !errors.Is(err, izidic.ErrCircularDependency) || !strings.HasSuffix(err.Error(), expected) {
+			t.Fatalf("got error %v for %s, but expected a self-resolution error", err, name)
+		}
+	}
+}
+
+func TestContainer_Meta(t *testing.T) {
+	dic := izidic.New()
+	meta := map[string]any{"owner": "team-a", "version": 2}
+	dic.RegisterWithMeta("s1", meta, s1)
+	dic.Register("s2", s2)
+
+	// Mutating the original map must not affect the stored metadata.
+	meta["owner"] = "team-b"
+	actual := dic.Meta("s1")
+	expected := map[string]any{"owner": "team-a", "version": 2}
+	if !cmp.Equal(actual, expected) {
+		t.Fatalf("unexpected metadata: %s", cmp.Diff(actual, expected))
+	}
+
+	// Mutating the returned map must not affect the stored metadata either.
+	actual["owner"] = "team-c"
+	if owner := dic.Meta("s1")["owner"]; owner != "team-a" {
+		t.Fatalf("got owner %v, but expected %q", owner, "team-a")
+	}
+
+	if actual := dic.Meta("s2"); actual != nil {
+		t.Fatalf("got %#v for service without metadata, but expected nil", actual)
+	}
+	if actual := dic.MustService("s1"); actual != "s1" {
+		t.Fatalf("got %#v, but expected %q", actual, "s1")
+	}
+}
+
+// node is the instance type built by randomDAG services.
+type node struct {
+	value string
+}
+
+// randomDAG registers n services with random dependencies on dic.
+//
+// Service i may only depend on services j < i, so the graph is acyclic.
+// Each service value is derived from the values of its dependencies, so that
+// it only depends on the graph shape, not on the resolution order.
+// The returned counts slice holds the number of times each factory ran.
+func randomDAG(dic izidic.Container, rnd *rand.Rand, n int) (names []string, counts []int32) {
+	names = make([]string, n)
+	counts = make([]int32, n)
+	for i := 0; i < n; i++ {
+		names[i] = fmt.Sprintf("s%02d", i)
+	}
+	for i := 0; i < n; i++ {
+		var deps []string
+		for j := 0; j < i; j++ {
+			if rnd.Intn(3) == 0 {
+				deps = append(deps, names[j])
+			}
+		}
+		i := i
+		dic.Register(names[i], func(dic izidic.Container) (any, error) {
+			atomic.AddInt32(&counts[i], 1)
+			values := make([]string, len(deps))
+			for k, dep := range deps {
+				d, err := dic.Service(dep)
+				if err != nil {
+					return nil, err
+				}
+				values[k] = d.(*node).value
+			}
+			return &node{value: names[i] + "(" + strings.Join(values, ",") + ")"}, nil
+		})
+	}
+	return names, counts
+}
+
+func FuzzContainer_Service_AccessOrder(f *testing.F) {
+	for _, seed := range []int64{0, 1, 42, 1e6} {
+		f.Add(seed, uint8(10))
+	}
+	f.Fuzz(func(t *testing.T, seed int64, size uint8) {
+		n := int(size%30) + 1
+
+		// Canonical order: resolve services in registration order.
+		canonical := izidic.New()
+		names, _ := randomDAG(canonical, rand.New(rand.NewSource(seed)), n)
+		expected := make(map[string]string, n)
+		for _, name := range names {
+			expected[name] = canonical.MustService(name).(*node).value
+		}
+
+		// Random sequential order: same values, each factory runs once.
+		rnd := rand.New(rand.NewSource(seed))
+		dic := izidic.New()
+		_, counts := randomDAG(dic, rand.New(rand.NewSource(seed)), n)
+		for _, i := range rnd.Perm(n) {
+			if actual := dic.MustService(names[i]).(*node).value; actual != expected[names[i]] {
+				t.Fatalf("service %s: got %q, but expected %q", names[i], actual, expected[names[i]])
+			}
+		}
+		for i, count := range counts {
+			if count != 1 {
+				t.Fatalf("service %s instantiated %d times", names[i], count)
+			}
+		}
+
+		// Random concurrent orders after freeze: all callers share the same instances.
+		dic = izidic.New()
+		randomDAG(dic, rand.New(rand.NewSource(seed)), n)
+		dic.Freeze()
+		const workers = 4
+		results := make([][]*node, workers)
+		wg := sync.WaitGroup{}
+		for w := 0; w < workers; w++ {
+			w, perm := w, rnd.Perm(n)
+			results[w] = make([]*node, n)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for _, i := range perm {
+					results[w][i] = dic.MustService(names[i]).(*node)
+				}
+			}()
+		}
+		wg.Wait()
+		for i, name := range names {
+			for w := 1; w < workers; w++ {
+				if results[w][i] != results[0][i] {
+					t.Fatalf("service %s: got distinct instances %p and %p", name, results[w][i], results[0][i])
+				}
+			}
+			if results[0][i].value != expected[name] {
+				t.Fatalf("service %s: got %q, but expected %q", name, results[0][i].value, expected[name])
+			}
+		}
+	})
+}
+
+func TestContainer_ServiceWith(t *testing.T) {
+	dic := izidic.New()
+	dic.Register("s1", s1)
+	dic.Register("s2", s2)
+	dic.Freeze()
+
+	actual, err := dic.ServiceWith("s2", map[string]any{"s1": "o1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual != "o1s2" {
+		t.Fatalf("got %#v, but expected %q", actual, "o1s2")
+	}
+
+	// The base container cache is unaffected.
+	if actual := dic.MustService("s2"); actual != "s1s2" {
+		t.Fatalf("got %#v, but expected %q", actual, "s1s2")
+	}
+	if actual, _ := dic.ServiceWith("s2", nil); actual != "s1s2" {
+		t.Fatalf("got %#v, but expected %q", actual, "s1s2")
+	}
+}
+
+func TestContainer_ServiceWith_childState(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	dic := izidic.New(izidic.WithClock(clock))
+	dic.RegisterCached("token", func(izidic.Container) (any, error) { return "fresh", nil }, time.Minute)
+	dic.Register("client", func(dic izidic.Container) (any, error) { return dic.Service("token") })
+	dic.Register("legacy", func(dic izidic.Container) (any, error) { return dic.Service("old") })
+	dic.Remove("old", "v2.0.0")
+	dic.Freeze()
+
+	actual, err := dic.ServiceWith("client", map[string]any{"token": "stub"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual != "stub" {
+		t.Fatalf("got %#v, but expected the override of the cached service", actual)
+	}
+
+	// The child reports removed names like the container.
+	_, err = dic.ServiceWith("legacy", nil)
+	if err == nil || !strings.Contains(err.Error(), "was removed in v2.0.0") {
+		t.Fatalf("got error %v, but expected a removal error", err)
+	}
+}
+
+func TestContainer_ServiceWithParams(t *testing.T) {
+	dic := izidic.New()
+	dic.Store("timeout", time.Second)
+	if err := dic.AliasParam("delay", "timeout"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dic.Register("client", func(dic izidic.Container) (any, error) {
+		return dic.MustParam("delay"), nil
+	})
+	dic.Register("app", func(dic izidic.Container) (any, error) {
+		return dic.Service("client")
+	})
+	dic.Freeze()
+	if actual := dic.MustService("app"); actual != time.Second {
+		t.Fatalf("got %v, but expected %s", actual, time.Second)
+	}
+
+	actual, err := dic.ServiceWithParams("app", map[string]any{"delay": time.Minute})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual != time.Minute {
+		t.Fatalf("got %v, but expected the dependency to see %s", actual, time.Minute)
+	}
+
+	// The base container is unaffected.
+	if actual := dic.MustService("app"); actual != time.Second {
+		t.Fatalf("got %v, but expected %s", actual, time.Second)
+	}
+	if actual := dic.MustParam("delay"); actual != time.Second {
+		t.Fatalf("got %v, but expected %s", actual, time.Second)
+	}
+}
+
+func TestContainer_Seal(t *testing.T) {
+	dic := izidic.New()
+	dic.Register("s1", s1)
+	if actual := dic.Seal(); actual != dic {
+		t.Fatalf("got %v, but expected the container itself", actual)
+	}
+	if actual := dic.State(); actual != izidic.Frozen {
+		t.Fatalf("got state %s, but expected %s", actual, izidic.Frozen)
+	}
+}
+
+func TestContainer_Rebuild(t *testing.T) {
+	calls := 0
+	dic := izidic.New()
+	dic.Register("counter", func(izidic.Container) (any, error) {
+		calls++
+		return calls, nil
+	})
+	dic.Register("dependent", func(dic izidic.Container) (any, error) {
+		return dic.MustService("counter"), nil
+	})
+	dic.Freeze()
+
+	if actual := dic.MustService("dependent"); actual != 1 {
+		t.Fatalf("got %v, but expected 1", actual)
+	}
+	actual, err := dic.Rebuild("counter")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual != 2 || dic.MustService("counter") != 2 {
+		t.Fatalf("got %v, but expected the rebuilt instance 2", actual)
+	}
+	// Dependents keep the previous instance.
+	if actual := dic.MustService("dependent"); actual != 1 {
+		t.Fatalf("got %v, but expected 1", actual)
+	}
+
+	if _, err := dic.Rebuild("missing"); fmt.Sprint(err) != `service not found: "missing"` {
+		t.Fatalf("got error %v, but expected a not found error", err)
+	}
+}
+
+func TestContainer_Rebuild_Budget(t *testing.T) {
+	dic := izidic.New(izidic.WithResolutionBudget(10 * time.Millisecond))
+	dic.Register("slow", func(izidic.Container) (any, error) {
+		time.Sleep(20 * time.Millisecond)
+		return "slow", nil
+	})
+	dic.Register("s1", s1)
+	dic.Freeze()
+	if _, err := dic.Rebuild("slow"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual := dic.Stats().Instantiations; actual != 1 {
+		t.Fatalf("got %d instantiations, but expected the rebuild to count", actual)
+	}
+	if _, err := dic.Rebuild("s1"); !errors.Is(err, izidic.ErrBudgetExceeded) {
+		t.Fatalf("got error %v, but expected the rebuild to spend the budget", err)
+	}
+}
+
+func TestContainer_SetRegisterInterceptor(t *testing.T) {
+	var intercepted []string
+	dic := izidic.New()
+	dic.Register("s1", s1)
+	dic.SetRegisterInterceptor(func(name string, fn izidic.Service) izidic.Service {
+		intercepted = append(intercepted, name)
+		return func(dic izidic.Container) (any, error) {
+			instance, err := fn(dic)
+			return "[" + instance.(string) + "]", err
+		}
+	})
+	dic.Register("s2", s2)
+	dic.RegisterWithMeta("s3", nil, func(izidic.Container) (any, error) { return "s3", nil })
+	dic.Freeze()
+
+	if expected := []string{"s2", "s3"}; !cmp.Equal(intercepted, expected) {
+		t.Fatalf("unexpected interceptions: %s", cmp.Diff(intercepted, expected))
+	}
+	for name, expected := range map[string]string{"s1": "s1", "s2": "[s1s2]", "s3": "[s3]"} {
+		if actual := dic.MustService(name); actual != expected {
+			t.Errorf("got %#v for %s, but expected %q", actual, name, expected)
+		}
+	}
+}
+
+func TestContainer_Unused(t *testing.T) {
+	dic := izidic.New()
+	dic.Register("s1", s1)
+	dic.Register("s2", s2)
+	dic.Register("s3", s1)
+	dic.Freeze()
+
+	if expected, actual := []string{"s1", "s2", "s3"}, dic.Unused(); !cmp.Equal(actual, expected) {
+		t.Fatalf("unexpected unused services: %s", cmp.Diff(actual, expected))
+	}
+	dic.MustService("s2")
+	if expected, actual := []string{"s3"}, dic.Unused(); !cmp.Equal(actual, expected) {
+		t.Fatalf("unexpected unused services: %s", cmp.Diff(actual, expected))
+	}
+}
+
+func TestContainer_Names_ConcurrentBuild(t *testing.T) {
+	const n = 100
+	dic := izidic.New()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < n; i++ {
+			dic.Register(fmt.Sprintf("s%d", i), s1)
+			dic.Store(fmt.Sprintf("p%d", i), i)
+		}
+	}()
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+			dic.Names()
+		}
+	}
+	names := dic.Names()
+	if len(names["params"]) != n || len(names["services"]) != n {
+		t.Fatalf("got %d params and %d services, but expected %d of each",
+			len(names["params"]), len(names["services"]), n)
+	}
+}
+
+func TestContainer_NamespaceHints(t *testing.T) {
+	dic := izidic.New()
+	dic.Store("p", "v")
+	dic.Register("s1", s1)
+	dic.Freeze()
+
+	tests := [...]struct {
+		name     string
+		attempt  func() error
+		expected string
+	}{
+		{"param is service", func() error { _, err := dic.Param("s1"); return err },
+			`parameter not found: "s1" is a service, not a parameter; use Service()`},
+		{"service is param", func() error { _, err := dic.Service("p"); return err },
+			`service not found: "p" is a parameter, not a service; use Param()`},
+		{"param missing", func() error { _, err := dic.Param("x"); return err }, `parameter not found: "x"`},
+		{"service missing", func() error { _, err := dic.Service("x"); return err }, `service not found: "x"`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.attempt(); err == nil || err.Error() != test.expected {
+				t.Fatalf("got error %v, but expected %q", err, test.expected)
+			}
+		})
+	}
+}
+
+func TestContainer_Services(t *testing.T) {
+	dic := izidic.New()
+	dic.Register("s1", s1)
+	dic.Register("s2", s2)
+	dic.Freeze()
+
+	actual, err := dic.Services("s2", "s1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []any{"s1s2", "s1"}; !cmp.Equal(actual, expected) {
+		t.Fatalf("unexpected instances: %s", cmp.Diff(actual, expected))
+	}
+
+	actual, err = dic.Services("s1", "k2", "s2")
+	if expected := `resolving service 1 "k2": service not found: "k2"`; actual != nil || fmt.Sprint(err) != expected {
+		t.Fatalf("got %v, %v, but expected error %q", actual, err, expected)
+	}
+}
@@ -0,0 +1,150 @@
+// Package izidicdebug provides an HTTP handler exposing the introspection data
+// of an izidic container, for live debugging of a running application.
+//
+// It lives in its own package to avoid importing net/http into izidic itself.
+package izidicdebug
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fgm/izidic"
+)
+
+// Counts summarizes the container contents.
+type Counts struct {
+	Params       int `json:"params"`
+	Services     int `json:"services"`
+	Instantiated int `json:"instantiated"`
+}
+
+// Instantiation describes the first instantiation of a service.
+type Instantiation struct {
+	Name string `json:"name"`
+	// Duration is the time spent in the factory, including its dependencies.
+	Duration time.Duration `json:"duration"`
+	// Self is the part of Duration not spent resolving dependencies.
+	Self time.Duration `json:"self"`
+}
+
+// Stats are the resolution counts of the container, as returned by its Stats method.
+type Stats struct {
+	Resolutions    uint64 `json:"resolutions"`
+	Hits           uint64 `json:"hits"`
+	Instantiations uint64 `json:"instantiations"`
+	Errors         uint64 `json:"errors"`
+}
+
+// Report is the introspection data served by the handler.
+type Report struct {
+	State    string   `json:"state"`
+	Counts   Counts   `json:"counts"`
+	Params   []string `json:"params"`
+	Services []string `json:"services"`
+	Unused   []string `json:"unused"`
+	// Instantiations lists the instantiated services in instantiation order,
+	// with their timings.
+	Instantiations []Instantiation `json:"instantiations"`
+	// Dependencies lists the services requested by each service, only known
+	// when recorded by FreezeWithAnalysis, and empty otherwise.
+	Dependencies map[string][]string `json:"dependencies"`
+	Stats        Stats               `json:"stats"`
+}
+
+// NewReport builds a Report from the container inventory, instantiation
+// manifest, dependency matrix, and stats.
+//
+// It does not resolve any parameter or service.
+func NewReport(dic izidic.Container) Report {
+	stats := dic.Stats()
+	report := Report{
+		State:          dic.State().String(),
+		Params:         []string{},
+		Services:       []string{},
+		Unused:         []string{},
+		Instantiations: []Instantiation{},
+		Dependencies:   map[string][]string{},
+		Stats: Stats{
+			Resolutions:    stats.Resolutions,
+			Hits:           stats.Hits,
+			Instantiations: stats.Instantiations,
+			Errors:         stats.Errors,
+		},
+	}
+	for _, entry := range dic.Inventory() {
+		switch entry.Kind {
+		case izidic.KindParam:
+			report.Params = append(report.Params, entry.Name)
+		case izidic.KindService:
+			report.Services = append(report.Services, entry.Name)
+			if entry.Instantiated {
+				report.Counts.Instantiated++
+			} else {
+				report.Unused = append(report.Unused, entry.Name)
+			}
+		}
+	}
+	report.Counts.Params = len(report.Params)
+	report.Counts.Services = len(report.Services)
+	for _, entry := range dic.InstantiationManifest() {
+		report.Instantiations = append(report.Instantiations,
+			Instantiation{Name: entry.Name, Duration: entry.Duration, Self: entry.Self})
+	}
+	for name, row := range dic.DependencyMatrix() {
+		deps := make([]string, 0, len(row))
+		for dep := range row {
+			deps = append(deps, dep)
+		}
+		sort.Strings(deps)
+		report.Dependencies[name] = deps
+	}
+	return report
+}
+
+var page = template.Must(template.New("izidic").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><title>izidic</title></head>
+<body>
+<h1>izidic container: {{ .State }}</h1>
+<p>{{ .Counts.Params }} parameters, {{ .Counts.Services }} services, {{ .Counts.Instantiated }} instantiated.</p>
+<h2>Parameters</h2>
+<ul>{{ range .Params }}<li>{{ . }}</li>{{ end }}</ul>
+<h2>Services</h2>
+<ul>{{ range .Services }}<li>{{ . }}</li>{{ end }}</ul>
+<h2>Unused services</h2>
+<ul>{{ range .Unused }}<li>{{ . }}</li>{{ end }}</ul>
+<h2>Instantiations</h2>
+<ol>{{ range .Instantiations }}<li>{{ .Name }}: {{ .Duration }} ({{ .Self }} self)</li>{{ end }}</ol>
+<h2>Dependencies</h2>
+<ul>{{ range $name, $deps := .Dependencies }}<li>{{ $name }}: {{ range $deps }}{{ . }} {{ end }}</li>{{ end }}</ul>
+<p>{{ .Stats.Resolutions }} resolutions, {{ .Stats.Hits }} hits, {{ .Stats.Instantiations }} instantiations, {{ .Stats.Errors }} errors.</p>
+</body>
+</html>
+`))
+
+// DebugHandler returns a handler serving the container Report, as HTML if the
+// request accepts it, or JSON otherwise.
+//
+// It is typically mounted under /debug/izidic, like:
+//
+//	mux.Handle("/debug/izidic/", http.StripPrefix("/debug/izidic", izidicdebug.DebugHandler(dic)))
+func DebugHandler(dic izidic.Container) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" && r.URL.Path != "" {
+			http.NotFound(w, r)
+			return
+		}
+		report := NewReport(dic)
+		if strings.Contains(r.Header.Get("Accept"), "text/html") {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_ = page.Execute(w, report)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
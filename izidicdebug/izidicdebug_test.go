@@ -0,0 +1,78 @@
+package izidicdebug_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fgm/izidic"
+	"github.com/fgm/izidic/izidicdebug"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func newContainer() izidic.Container {
+	dic := izidic.New()
+	dic.Store("name", "demo")
+	dic.Register("helper", func(izidic.Container) (any, error) { return 0, nil })
+	dic.Register("used", func(dic izidic.Container) (any, error) { return dic.Service("helper") })
+	dic.Register("unused", func(izidic.Container) (any, error) { return 2, nil })
+	if err := dic.FreezeWithAnalysis(); err != nil {
+		panic(err)
+	}
+	dic.MustService("used")
+	return dic
+}
+
+func TestDebugHandler_JSON(t *testing.T) {
+	h := izidicdebug.DebugHandler(newContainer())
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("got content type %q, but expected JSON", ct)
+	}
+	var actual izidicdebug.Report
+	if err := json.NewDecoder(rec.Body).Decode(&actual); err != nil {
+		t.Fatalf("failed decoding report: %v", err)
+	}
+	expected := izidicdebug.Report{
+		State:    "frozen",
+		Counts:   izidicdebug.Counts{Params: 1, Services: 3, Instantiated: 2},
+		Params:   []string{"name"},
+		Services: []string{"helper", "unused", "used"},
+		Unused:   []string{"unused"},
+		Instantiations: []izidicdebug.Instantiation{
+			{Name: "helper"},
+			{Name: "used"},
+		},
+		Dependencies: map[string][]string{"used": {"helper"}},
+		Stats:        izidicdebug.Stats{Resolutions: 2, Instantiations: 2},
+	}
+	// Timings vary between runs.
+	ignoreTimings := cmpopts.IgnoreFields(izidicdebug.Instantiation{}, "Duration", "Self")
+	if !cmp.Equal(actual, expected, ignoreTimings) {
+		t.Fatalf("unexpected report: %s", cmp.Diff(actual, expected, ignoreTimings))
+	}
+}
+
+func TestDebugHandler_HTML(t *testing.T) {
+	h := izidicdebug.DebugHandler(newContainer())
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	h.ServeHTTP(rec, req)
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Fatalf("got content type %q, but expected HTML", ct)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, "<li>unused</li>") {
+		t.Fatalf("unexpected body: %s", body)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/other", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("got status %d, but expected %d", rec.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,134 @@
+// Package izidiclint provides a go/analysis analyzer reporting likely wiring
+// errors in code using izidic containers.
+//
+// It can be run standalone with the singlechecker package:
+//
+//	func main() { singlechecker.Main(izidiclint.Analyzer) }
+package izidiclint
+
+import (
+	"go/ast"
+	"go/constant"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const izidicPath = "github.com/fgm/izidic"
+
+// Analyzer reports service lookups using a constant name which is not registered
+// anywhere in the same package.
+//
+// Lookups are calls to AcquireT, Into, MustService, MustServiceN, Rebuild, Service,
+// ServiceN, Services, ServiceVersion, ServiceWith, ServiceWithParams, and Try. Registrations are calls to ProvideFn, Register,
+// RegisterN, RegisterAsserting, RegisterCached, RegisterLogged, RegisterNilable,
+// RegisterProfile, RegisterWithMeta, RegisterVersioned, RegisterWithRetry,
+// RegisterWithTimeout, and WithInstance.
+// Names may be string literals or any constant, including Name constants.
+//
+// Since services are often consumed outside the package defining them,
+// packages without any registration are not checked.
+var Analyzer = &analysis.Analyzer{
+	Name:     "izidicundefined",
+	Doc:      "report izidic service lookups for names not registered in the same package",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// allArgs is the index of the name argument for variadic lookups, all of whose
+// arguments are names.
+const allArgs = -1
+
+// lookups maps the functions and methods resolving services to the index of their name argument.
+var lookups = map[string]int{
+	"AcquireT":          1,
+	"Into":              0,
+	"MustService":       0,
+	"MustServiceN":      0,
+	"Rebuild":           0,
+	"Service":           0,
+	"ServiceN":          0,
+	"Services":          allArgs,
+	"ServiceVersion":    0,
+	"ServiceWith":       0,
+	"ServiceWithParams": 0,
+	"Try":               1,
+}
+
+// registrations maps the functions and methods defining services to the index of their name argument.
+var registrations = map[string]int{
+	"ProvideFn":           1,
+	"Register":            0,
+	"RegisterN":           0,
+	"RegisterAsserting":   1,
+	"RegisterCached":      0,
+	"RegisterLogged":      0,
+	"RegisterNilable":     0,
+	"RegisterProfile":     1,
+	"RegisterWithMeta":    0,
+	"RegisterVersioned":   0,
+	"RegisterWithRetry":   0,
+	"RegisterWithTimeout": 0,
+	"WithInstance":        0,
+}
+
+type lookup struct {
+	arg  ast.Expr
+	name string
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	registered := make(map[string]bool)
+	var found []lookup
+
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	ins.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		callee := typeutil.Callee(pass.TypesInfo, call)
+		if callee == nil || callee.Pkg() == nil || callee.Pkg().Path() != izidicPath {
+			return
+		}
+		if idx, ok := registrations[callee.Name()]; ok {
+			if name, ok := constantName(pass, call, idx); ok {
+				registered[name] = true
+			}
+			return
+		}
+		idx, ok := lookups[callee.Name()]
+		if !ok {
+			return
+		}
+		for i := range call.Args {
+			if i != idx && idx != allArgs {
+				continue
+			}
+			if name, ok := constantName(pass, call, i); ok {
+				found = append(found, lookup{arg: call.Args[i], name: name})
+			}
+		}
+	})
+
+	if len(registered) == 0 {
+		return nil, nil
+	}
+	for _, l := range found {
+		if !registered[l.name] {
+			pass.Reportf(l.arg.Pos(), "service %q is not registered in this package", l.name)
+		}
+	}
+	return nil, nil
+}
+
+// constantName returns the value of the call argument at idx if it is a string constant.
+func constantName(pass *analysis.Pass, call *ast.CallExpr, idx int) (string, bool) {
+	if idx >= len(call.Args) {
+		return "", false
+	}
+	tv, ok := pass.TypesInfo.Types[call.Args[idx]]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
@@ -0,0 +1,12 @@
+package izidiclint_test
+
+import (
+	"testing"
+
+	"github.com/fgm/izidic/izidiclint"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), izidiclint.Analyzer, "a", "b")
+}
@@ -0,0 +1,54 @@
+package a
+
+import (
+	"io"
+	"time"
+
+	"github.com/fgm/izidic"
+)
+
+const nameDB izidic.Name = "db"
+
+// Container mimics an application wrapper with typed accessors.
+type Container struct {
+	izidic.Container
+}
+
+func (c Container) Logger() any {
+	return c.MustService("loger") // want `service "loger" is not registered in this package`
+}
+
+func resolve() {
+	dic := izidic.New(izidic.WithInstance("server", nil))
+	dic.Register("logger", nil)
+	dic.RegisterN(nameDB, nil)
+	dic.RegisterCached("token", nil, time.Minute)
+	dic.RegisterWithTimeout("client", nil, time.Second)
+	dic.RegisterProfile("test", "mailer", nil)
+	dic.RegisterLogged("audit", nil)
+	izidic.RegisterAsserting[string](dic, "greeting", nil)
+	dic.RegisterNilable("optional", nil)
+	izidic.ProvideFn[string](dic, "ctor", nil)
+
+	dic.MustService("logger")
+	dic.MustServiceN(nameDB)
+	dic.MustService("token")
+	dic.MustService("client")
+	dic.MustService("mailer")
+	dic.MustService("audit")
+	dic.MustService("greeting")
+	dic.MustService("optional")
+	dic.MustService("ctor")
+	dic.MustService("test") // want `service "test" is not registered in this package`
+	dic.Service("server")
+	dic.Service("dbs")                            // want `service "dbs" is not registered in this package`
+	izidic.Try[string](dic, "missing")            // want `service "missing" is not registered in this package`
+	izidic.AcquireT[io.ReadCloser](dic, "clinet") // want `service "clinet" is not registered in this package`
+	dic.Into("loger", nil)                        // want `service "loger" is not registered in this package`
+	dic.Rebuild("tokne")                          // want `service "tokne" is not registered in this package`
+	dic.ServiceWithParams("mailr", nil)           // want `service "mailr" is not registered in this package`
+	dic.Services("logger", "adit", "token")       // want `service "adit" is not registered in this package`
+
+	name := "dynamic"
+	dic.Service(name) // Not a constant: not checked.
+}
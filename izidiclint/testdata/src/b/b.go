@@ -0,0 +1,8 @@
+// Package b only consumes services, so it is not checked.
+package b
+
+import "github.com/fgm/izidic"
+
+func use(dic izidic.Container) {
+	dic.MustService("anything")
+}
@@ -0,0 +1,49 @@
+// Package izidic is a minimal stub of the real package for analyzer tests.
+package izidic
+
+import (
+	"io"
+	"time"
+)
+
+type Name string
+
+type Service func(dic Container) (any, error)
+
+type LoggedService func(dic Container, logger any) (any, error)
+
+type Container interface {
+	Into(name string, target any) error
+	MustService(name string) any
+	MustServiceN(name Name) any
+	Register(name string, fn Service)
+	RegisterCached(name string, fn Service, ttl time.Duration)
+	RegisterN(name Name, fn Service)
+	RegisterNilable(name string, fn Service)
+	RegisterLogged(name string, fn LoggedService)
+	RegisterProfile(profile, name string, fn Service)
+	RegisterWithTimeout(name string, fn Service, d time.Duration)
+	Rebuild(name string) (any, error)
+	Service(name string) (any, error)
+	Services(names ...string) ([]any, error)
+	ServiceWithParams(name string, params map[string]any) (any, error)
+}
+
+type Option func()
+
+func New(opts ...Option) Container { return nil }
+
+func WithInstance(name string, instance any) Option { return nil }
+
+func RegisterAsserting[T any](dic Container, name string, fn Service) {}
+
+func ProvideFn[T any](dic Container, name string, ctor any) {}
+
+type Result[T any] struct{}
+
+func Try[T any](dic Container, name string) Result[T] { return Result[T]{} }
+
+func AcquireT[T io.Closer](dic Container, name string) (T, error) {
+	var zero T
+	return zero, nil
+}
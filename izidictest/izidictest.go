@@ -0,0 +1,143 @@
+// Package izidictest provides helpers for testing code using izidic containers.
+//
+// It lives in its own package to avoid importing testing into izidic itself.
+package izidictest
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+// Mock is a container verifying which services the code under test resolves
+// from it, like a mock verifying calls:
+//
+//	dic := izidictest.NewMock().Expect("db", "logger")
+//	dic.Register("db", newDB)
+//	dic.Register("logger", newLogger)
+//	dic.Freeze()
+//	unit(dic)
+//	dic.VerifyExpectations(t)
+//
+// Only the resolutions made directly on the Mock, with Service, ServiceN,
+// Services, or their Must variants, are tracked: those made by factories, on
+// the container passed to them, are not.
+type Mock struct {
+	izidic.Container
+
+	mu       sync.Mutex
+	expected map[string]bool
+	resolved map[string]bool
+}
+
+// NewMock creates a Mock over a new container created with the given options.
+func NewMock(opts ...izidic.Option) *Mock {
+	return &Mock{
+		Container: izidic.New(opts...),
+		expected:  make(map[string]bool),
+		resolved:  make(map[string]bool),
+	}
+}
+
+// Expect declares services which the code under test is expected to resolve,
+// returning the Mock to allow chaining.
+func (m *Mock) Expect(names ...string) *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, name := range names {
+		m.expected[name] = true
+	}
+	return m
+}
+
+// VerifyExpectations fails the test for each expected service which was never
+// resolved, and for each resolved service which was not expected, in name order.
+//
+// Resolutions are tracked whether they succeeded or not.
+func (m *Mock) VerifyExpectations(t testing.TB) {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, name := range sortedKeys(m.expected) {
+		if !m.resolved[name] {
+			t.Errorf("expected service %q was never resolved", name)
+		}
+	}
+	for _, name := range sortedKeys(m.resolved) {
+		if !m.expected[name] {
+			t.Errorf("unexpected resolution of service %q", name)
+		}
+	}
+}
+
+func (m *Mock) MustService(name string) any {
+	m.track(name)
+	return m.Container.MustService(name)
+}
+
+func (m *Mock) MustServiceN(name izidic.Name) any {
+	return m.MustService(string(name))
+}
+
+func (m *Mock) Service(name string) (any, error) {
+	m.track(name)
+	return m.Container.Service(name)
+}
+
+func (m *Mock) ServiceN(name izidic.Name) (any, error) {
+	return m.Service(string(name))
+}
+
+func (m *Mock) Services(names ...string) ([]any, error) {
+	for _, name := range names {
+		m.track(name)
+	}
+	return m.Container.Services(names...)
+}
+
+// AssertNoEagerInstantiation fails the test if the container returned by
+// resolve, typically the function building and freezing the application
+// container, instantiated any service, to guard the laziness of the wiring
+// against registration code resolving services during the build.
+//
+// Instantiations are the services having a cached instance in
+// Container.Inventory, which is recorded even under WithIntrospection(false),
+// and those listed by Container.InstantiationManifest, which also covers
+// factories registered with RegisterN.
+func AssertNoEagerInstantiation(t testing.TB, resolve func() izidic.Container) {
+	t.Helper()
+	dic := resolve()
+	instantiated := make(map[string]bool)
+	for _, entry := range dic.Inventory() {
+		if entry.Instantiated {
+			instantiated[entry.Name] = true
+		}
+	}
+	for _, entry := range dic.InstantiationManifest() {
+		instantiated[entry.Name] = true
+	}
+	if len(instantiated) == 0 {
+		return
+	}
+	t.Errorf("services instantiated during build: %s", strings.Join(sortedKeys(instantiated), ", "))
+}
+
+// track records the resolution of a service.
+func (m *Mock) track(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.resolved[name] = true
+}
+
+// sortedKeys returns the sorted keys of a set.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,88 @@
+package izidictest_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fgm/izidic"
+	"github.com/fgm/izidic/izidictest"
+	"github.com/google/go-cmp/cmp"
+)
+
+// recordingT captures the failures reported by VerifyExpectations.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestMock_VerifyExpectations(t *testing.T) {
+	checks := [...]struct {
+		name     string
+		expect   []string
+		resolve  []string
+		expected []string
+	}{
+		{"met", []string{"db", "logger"}, []string{"logger", "db"}, nil},
+		{"never resolved", []string{"db", "logger"}, []string{"db"}, []string{
+			`expected service "logger" was never resolved`,
+		}},
+		{"unexpected", []string{"db"}, []string{"db", "logger", "missing"}, []string{
+			`unexpected resolution of service "logger"`,
+			`unexpected resolution of service "missing"`,
+		}},
+	}
+	for _, check := range checks {
+		t.Run(check.name, func(t *testing.T) {
+			dic := izidictest.NewMock().Expect(check.expect...)
+			dic.Register("db", func(izidic.Container) (any, error) { return "db", nil })
+			dic.Register("logger", func(izidic.Container) (any, error) { return "logger", nil })
+			dic.Freeze()
+			for _, name := range check.resolve {
+				_, _ = dic.Service(name)
+			}
+
+			rt := &recordingT{TB: t}
+			dic.VerifyExpectations(rt)
+			if !cmp.Equal(rt.errors, check.expected) {
+				t.Fatalf("unexpected failures: %s", cmp.Diff(rt.errors, check.expected))
+			}
+		})
+	}
+}
+
+func TestAssertNoEagerInstantiation(t *testing.T) {
+	checks := [...]struct {
+		name     string
+		eager    bool
+		opaque   bool
+		expected []string
+	}{
+		{"lazy", false, false, nil},
+		{"eager", true, false, []string{"services instantiated during build: db, repo"}},
+		{"eager without introspection", true, true, []string{"services instantiated during build: db, repo"}},
+	}
+	for _, check := range checks {
+		t.Run(check.name, func(t *testing.T) {
+			rt := &recordingT{TB: t}
+			izidictest.AssertNoEagerInstantiation(rt, func() izidic.Container {
+				dic := izidic.New(izidic.WithIntrospection(!check.opaque))
+				dic.Register("db", func(izidic.Container) (any, error) { return "db", nil })
+				dic.Register("repo", func(dic izidic.Container) (any, error) { return dic.Service("db") })
+				if check.eager {
+					dic.MustService("repo")
+				}
+				dic.Freeze()
+				return dic
+			})
+			if !cmp.Equal(rt.errors, check.expected) {
+				t.Fatalf("unexpected failures: %s", cmp.Diff(rt.errors, check.expected))
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package izidic
+
+import "log/slog"
+
+// LoggedService is a Service factory also receiving a logger dedicated to the
+// service, as registered by RegisterLogged.
+type LoggedService func(dic Container, log *slog.Logger) (any, error)
+
+// RegisterLogged registers a service whose factory receives a child of the
+// logger set by WithLogger, or of slog.Default at registration time without
+// it, so that structured logging is consistent across factories.
+//
+// The child logger has a "service" attribute holding the service name.
+// Factories not needing a logger keep using Register.
+func (dic *container) RegisterLogged(name string, fn LoggedService) {
+	name = dic.normalize(name)
+	if fn == nil {
+		mustFactory(name, nil)
+	}
+	dic.RLock()
+	logger := dic.logger
+	dic.RUnlock()
+	if logger == nil {
+		logger = slog.Default()
+	}
+	log := logger.With("service", name)
+	dic.register(name, func(dic Container) (any, error) {
+		return fn(dic, log)
+	}, nil)
+}
@@ -0,0 +1,28 @@
+package izidic_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+func TestContainer_RegisterLogged(t *testing.T) {
+	buf := bytes.Buffer{}
+	dic := izidic.New(izidic.WithLogger(slog.New(slog.NewTextHandler(&buf, nil))))
+	dic.Register("s1", s1)
+	dic.RegisterLogged("logged", func(dic izidic.Container, log *slog.Logger) (any, error) {
+		log.Info("building")
+		return dic.MustService("s1").(string) + "logged", nil
+	})
+	dic.Freeze()
+
+	if actual := dic.MustService("logged"); actual != "s1logged" {
+		t.Fatalf("got %v, but expected s1logged", actual)
+	}
+	if actual := buf.String(); !strings.Contains(actual, "msg=building service=logged") {
+		t.Fatalf("got log %q, but expected it to be annotated with the service name", actual)
+	}
+}
@@ -0,0 +1,50 @@
+package izidic
+
+import "time"
+
+// ManifestEntry describes the first instantiation of a service.
+type ManifestEntry struct {
+	Name  string `json:"name"`
+	Order int    `json:"order"` // Zero-based index in the instantiation sequence.
+	// Duration is the time spent in the factory, including the instantiation of
+	// its dependencies. It varies between runs, so it is not serialized to JSON.
+	Duration time.Duration `json:"-"`
+	// Self is the part of Duration not spent resolving services from the
+	// container passed to the factory.
+	Self time.Duration `json:"-"`
+	// Built lists the services first instantiated by the resolutions of the
+	// factory, in order, forming the instantiation tree used by WriteFlameGraph.
+	Built []string `json:"-"`
+}
+
+// InstantiationManifest returns the services instantiated so far, in the order
+// their factories completed, so dependencies come before their dependents.
+// Only the first instantiation of each service is listed.
+//
+// After a sequential warmup, like resolving the services in a fixed order or
+// WarmupParallel without analysis, the order only depends on the definitions,
+// so the JSON serialization of the manifest can be committed as a golden file,
+// for tests to detect changes in the startup sequence.
+func (dic *container) InstantiationManifest() []ManifestEntry {
+	dic.RLock()
+	defer dic.RUnlock()
+	return append([]ManifestEntry(nil), dic.manifest...)
+}
+
+// record adds the first instantiation of a service to the manifest, with the
+// timings and instantiations tracked by the view passed to its factory.
+//
+// Callers must hold the lock.
+func (dic *container) record(name string, elapsed time.Duration, view *factoryView) {
+	if dic.manifested[name] || dic.opaque {
+		return
+	}
+	dic.manifested[name] = true
+	self := max(elapsed-time.Duration(view.nested.Load()), 0)
+	view.mu.Lock()
+	built := append([]string(nil), view.built...)
+	view.mu.Unlock()
+	dic.manifest = append(dic.manifest, ManifestEntry{
+		Name: name, Order: len(dic.manifest), Duration: elapsed, Self: self, Built: built,
+	})
+}
@@ -0,0 +1,33 @@
+package izidic_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+func TestContainer_InstantiationManifest(t *testing.T) {
+	dic := izidic.New()
+	dic.Register("s1", s1)
+	dic.Register("s2", s2)
+	dic.Register("other", dependent("other"))
+	dic.Freeze()
+	if manifest := dic.InstantiationManifest(); manifest != nil {
+		t.Fatalf("got manifest %v before instantiation, but expected none", manifest)
+	}
+
+	dic.MustService("s2")
+	dic.Invalidate("s1")
+	dic.MustService("s1")
+	dic.MustService("other")
+
+	actual, err := json.Marshal(dic.InstantiationManifest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	const expected = `[{"name":"s1","order":0},{"name":"s2","order":1},{"name":"other","order":2}]`
+	if string(actual) != expected {
+		t.Fatalf("got manifest %s, but expected %s", actual, expected)
+	}
+}
@@ -0,0 +1,181 @@
+package izidic
+
+import "fmt"
+
+// RegisterModule runs register to define the services owned by a module, which
+// may then only resolve services owned by the same module or by the modules it
+// imports, to enforce architectural boundaries within a single container:
+//
+//	dic.RegisterModule("billing", []string{"users"}, func(dic izidic.Container) {
+//		dic.Register("invoicer", newInvoicer) // May use "users" services, not "shipping" ones.
+//	})
+//
+// Services registered outside any module, like loggers or database pools, are
+// infrastructure available to all modules. Services registered in a module can
+// resolve them freely, and they can resolve module services without restriction.
+//
+// Boundaries are checked at resolution, for all the methods resolving services
+// on the container passed to a module service factory, including Services,
+// Into, Rebuild, ServiceVersion, ServiceWith, ServiceWithParams, and parameters
+// stored by StoreFromService, failing with an error naming both modules.
+//
+// It panics if the module is already registered, or if called within a module.
+func (dic *container) RegisterModule(name string, imports []string, register func(Container)) {
+	dic.Lock()
+	dic.mustBuild("register modules")
+	if dic.module != "" {
+		defer dic.Unlock()
+		panic(fmt.Sprintf("Cannot register module %s within module %s", name, dic.module))
+	}
+	if _, found := dic.imports[name]; found {
+		defer dic.Unlock()
+		panic(fmt.Sprintf("Cannot register module %s twice", name))
+	}
+	dic.imports[name] = make(map[string]bool, len(imports))
+	for _, imported := range imports {
+		dic.imports[name][imported] = true
+	}
+	dic.module = name
+	dic.Unlock()
+
+	defer func() {
+		dic.Lock()
+		defer dic.Unlock()
+		dic.module = ""
+	}()
+	register(dic)
+}
+
+// own records the ownership of a service by the module being registered, if
+// any, and returns the service to store, restricted to its module boundaries.
+//
+// Callers must hold the lock.
+func (dic *container) own(name string, fn Service) Service {
+	if dic.module == "" {
+		delete(dic.owners, name)
+		return fn
+	}
+	module := dic.module
+	dic.owners[name] = module
+	return func(dic Container) (any, error) {
+		return fn(&moduleView{Container: dic, module: module, service: name})
+	}
+}
+
+// moduleView is the Container passed to the factories of module services.
+type moduleView struct {
+	Container
+	module  string
+	service string
+}
+
+func (m *moduleView) base() *container {
+	return baseOf(m.Container)
+}
+
+func (m *moduleView) MustService(name string) any {
+	instance, err := m.Service(name)
+	if err != nil {
+		panic(err)
+	}
+	return instance
+}
+
+func (m *moduleView) MustServiceN(name Name) any {
+	return m.MustService(string(name))
+}
+
+func (m *moduleView) Service(name string) (any, error) {
+	if err := m.check(name); err != nil {
+		return nil, err
+	}
+	return m.Container.Service(name)
+}
+
+func (m *moduleView) ServiceN(name Name) (any, error) {
+	return m.Service(string(name))
+}
+
+func (m *moduleView) Services(names ...string) ([]any, error) {
+	for _, name := range names {
+		if err := m.check(name); err != nil {
+			return nil, err
+		}
+	}
+	return m.Container.Services(names...)
+}
+
+func (m *moduleView) Into(name string, target any) error {
+	if err := m.check(name); err != nil {
+		return err
+	}
+	return m.Container.Into(name, target)
+}
+
+func (m *moduleView) Rebuild(name string) (any, error) {
+	if err := m.check(name); err != nil {
+		return nil, err
+	}
+	return m.Container.Rebuild(name)
+}
+
+func (m *moduleView) ServiceVersion(name, version string) (any, error) {
+	if err := m.check(name); err != nil {
+		return nil, err
+	}
+	return m.Container.ServiceVersion(name, version)
+}
+
+func (m *moduleView) ServiceWith(name string, overrides map[string]any) (any, error) {
+	if err := m.check(name); err != nil {
+		return nil, err
+	}
+	return m.Container.ServiceWith(name, overrides)
+}
+
+func (m *moduleView) ServiceWithParams(name string, params map[string]any) (any, error) {
+	if err := m.check(name); err != nil {
+		return nil, err
+	}
+	return m.Container.ServiceWithParams(name, params)
+}
+
+func (m *moduleView) MustParam(name string) any {
+	p, err := m.Param(name)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func (m *moduleView) MustParamN(name Name) any {
+	return m.MustParam(string(name))
+}
+
+func (m *moduleView) Param(name string) (any, error) {
+	if service, derived := m.base().paramService(m.base().normalize(name)); derived {
+		if err := m.check(service); err != nil {
+			return nil, fmt.Errorf("parameter %q from service %q: %w", name, service, err)
+		}
+	}
+	return m.Container.Param(name)
+}
+
+func (m *moduleView) ParamN(name Name) (any, error) {
+	return m.Param(string(name))
+}
+
+// check returns an error if the module may not resolve the service.
+func (m *moduleView) check(name string) error {
+	dic := m.base()
+	name = dic.normalize(name)
+	dic.RLock()
+	owner, owned := dic.owners[name]
+	allowed := !owned || owner == m.module || dic.imports[m.module][owner]
+	dic.RUnlock()
+	if !allowed {
+		return fmt.Errorf("service %q of module %q cannot resolve service %q of module %q, which it does not import",
+			m.service, m.module, name, owner)
+	}
+	return nil
+}
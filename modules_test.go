@@ -0,0 +1,116 @@
+package izidic_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+func TestContainer_RegisterModule(t *testing.T) {
+	// uses builds a service resolving its dependencies.
+	uses := func(deps ...string) izidic.Service {
+		return func(dic izidic.Container) (any, error) {
+			for _, dep := range deps {
+				if _, err := dic.Service(dep); err != nil {
+					return nil, err
+				}
+			}
+			return deps, nil
+		}
+	}
+	dic := izidic.New()
+	dic.Register("logger", uses())
+	dic.Register("admin", uses("invoicer"))
+	dic.RegisterModule("users", nil, func(dic izidic.Container) {
+		dic.Register("accounts", uses("logger"))
+	})
+	dic.RegisterModule("shipping", nil, func(dic izidic.Container) {
+		dic.Register("carrier", uses())
+	})
+	dic.RegisterModule("billing", []string{"users"}, func(dic izidic.Container) {
+		dic.Register("taxes", uses())
+		dic.Register("invoicer", uses("logger", "taxes", "accounts"))
+		dic.Register("dispatcher", uses("carrier"))
+	})
+	dic.Freeze()
+
+	tests := [...]struct {
+		name     string
+		expected string
+	}{
+		{"admin", "<nil>"},
+		{"invoicer", "<nil>"},
+		{"dispatcher", `failed instantiating service dispatcher: service "dispatcher" of module "billing" cannot resolve service "carrier" of module "shipping", which it does not import`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := dic.Service(test.name); fmt.Sprint(err) != test.expected {
+				t.Fatalf("got error %v, but expected %s", err, test.expected)
+			}
+		})
+	}
+}
+
+func TestContainer_RegisterModule_AllResolutions(t *testing.T) {
+	const denied = `service "billing" of module "billing" cannot resolve service "repo" of module "users", which it does not import`
+	tests := [...]struct {
+		name    string
+		resolve func(dic izidic.Container) error
+	}{
+		{"Services", func(dic izidic.Container) error { _, err := dic.Services("repo"); return err }},
+		{"Into", func(dic izidic.Container) error { var s string; return dic.Into("repo", &s) }},
+		{"Rebuild", func(dic izidic.Container) error { _, err := dic.Rebuild("repo"); return err }},
+		{"ServiceWith", func(dic izidic.Container) error { _, err := dic.ServiceWith("repo", nil); return err }},
+		{"ServiceWithParams", func(dic izidic.Container) error { _, err := dic.ServiceWithParams("repo", nil); return err }},
+		{"Param", func(dic izidic.Container) error { _, err := dic.Param("repoParam"); return err }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dic := izidic.New()
+			dic.RegisterModule("users", nil, func(dic izidic.Container) {
+				dic.Register("repo", func(izidic.Container) (any, error) { return "repo", nil })
+			})
+			dic.StoreFromService("repoParam", "repo")
+			dic.RegisterModule("billing", nil, func(dic izidic.Container) {
+				dic.Register("billing", func(dic izidic.Container) (any, error) {
+					return nil, test.resolve(dic)
+				})
+			})
+			dic.Freeze()
+			_, err := dic.Service("billing")
+			if err == nil || !strings.Contains(err.Error(), denied) {
+				t.Fatalf("got error %v, but expected %q", err, denied)
+			}
+		})
+	}
+}
+
+func TestContainer_RegisterModule_Invalid(t *testing.T) {
+	tests := [...]struct {
+		name     string
+		register func(dic izidic.Container)
+		expected string
+	}{
+		{"twice", func(dic izidic.Container) {
+			dic.RegisterModule("m", nil, func(izidic.Container) {})
+			dic.RegisterModule("m", nil, func(izidic.Container) {})
+		}, "Cannot register module m twice"},
+		{"nested", func(dic izidic.Container) {
+			dic.RegisterModule("m", nil, func(dic izidic.Container) {
+				dic.RegisterModule("n", nil, func(izidic.Container) {})
+			})
+		}, "Cannot register module n within module m"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				if rec := recover(); fmt.Sprint(rec) != test.expected {
+					t.Fatalf("got panic %v, but expected %q", rec, test.expected)
+				}
+			}()
+			test.register(izidic.New())
+		})
+	}
+}
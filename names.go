@@ -0,0 +1,46 @@
+package izidic
+
+// Name is a typed parameter or service name.
+//
+// Declaring all names used by an application as Name constants in a single block
+// enumerates the valid names in one place, and funnels their use through
+// identifiers the compiler checks, instead of string literals where typos only
+// fail at runtime:
+//
+//	const (
+//		NameLogger izidic.Name = "logger"
+//		NameWriter izidic.Name = "writer"
+//	)
+//
+//	logger := dic.MustServiceN(NameLogger).(*log.Logger)
+type Name string
+
+// MustParamN is the Name-accepting version of MustParam.
+func (dic *container) MustParamN(name Name) any {
+	return dic.MustParam(string(name))
+}
+
+// MustServiceN is the Name-accepting version of MustService.
+func (dic *container) MustServiceN(name Name) any {
+	return dic.MustService(string(name))
+}
+
+// ParamN is the Name-accepting version of Param.
+func (dic *container) ParamN(name Name) (any, error) {
+	return dic.Param(string(name))
+}
+
+// RegisterN is the Name-accepting version of Register.
+func (dic *container) RegisterN(name Name, fn Service) {
+	dic.Register(string(name), fn)
+}
+
+// ServiceN is the Name-accepting version of Service.
+func (dic *container) ServiceN(name Name) (any, error) {
+	return dic.Service(string(name))
+}
+
+// StoreN is the Name-accepting version of Store.
+func (dic *container) StoreN(name Name, param any) {
+	dic.Store(string(name), param)
+}
@@ -0,0 +1,31 @@
+package izidic_test
+
+import (
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+const (
+	nameParam   izidic.Name = "p"
+	nameService izidic.Name = "s"
+)
+
+func TestContainer_NameMethods(t *testing.T) {
+	dic := izidic.New()
+	dic.StoreN(nameParam, "v")
+	dic.RegisterN(nameService, func(dic izidic.Container) (any, error) {
+		return dic.MustParamN(nameParam).(string) + "s", nil
+	})
+	dic.Freeze()
+
+	if p, err := dic.ParamN(nameParam); err != nil || p != "v" {
+		t.Fatalf("got %#v, %v, but expected %q", p, err, "v")
+	}
+	if s, err := dic.ServiceN(nameService); err != nil || s != "vs" {
+		t.Fatalf("got %#v, %v, but expected %q", s, err, "vs")
+	}
+	if s := dic.MustServiceN(nameService); s != dic.MustService(string(nameService)) {
+		t.Fatalf("got %#v from MustServiceN, unlike MustService", s)
+	}
+}
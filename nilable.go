@@ -0,0 +1,35 @@
+package izidic
+
+import "fmt"
+
+// RegisterNilable registers a service whose factory may legitimately return a
+// nil instance without an error, like an optional client disabled by
+// configuration.
+//
+// For other services, a factory returning (nil, nil) fails the resolution, since
+// it usually means an error path forgot to return its error, and the nil
+// instance would otherwise only fail later, on a type assertion far from the
+// faulty factory.
+func (dic *container) RegisterNilable(name string, fn Service) {
+	name = dic.normalize(name)
+	if !dic.register(name, fn, nil) {
+		return
+	}
+	dic.Lock()
+	defer dic.Unlock()
+	dic.nilable[name] = true
+}
+
+// checkNil reports a nil instance returned without error by the factory of a
+// service not registered by RegisterNilable.
+func (dic *container) checkNil(name string, instance any) error {
+	if instance != nil {
+		return nil
+	}
+	dic.RLock()
+	defer dic.RUnlock()
+	if dic.nilable[name] {
+		return nil
+	}
+	return fmt.Errorf("service %q factory returned nil without error", name)
+}
@@ -0,0 +1,28 @@
+package izidic_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+func TestContainer_RegisterNilable(t *testing.T) {
+	none := func(izidic.Container) (any, error) { return nil, nil }
+	dic := izidic.New()
+	dic.Register("forgotten", none)
+	dic.RegisterNilable("optional", none)
+	dic.RegisterNilable("replaced", none)
+	dic.Register("replaced", none) // No longer nilable.
+	dic.Freeze()
+
+	if actual, err := dic.Service("optional"); err != nil || actual != nil {
+		t.Fatalf("got %v, %v, but expected a nil instance without error", actual, err)
+	}
+	for _, name := range []string{"forgotten", "replaced"} {
+		expected := `service "` + name + `" factory returned nil without error`
+		if _, err := dic.Service(name); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("got error %v, but expected it to contain %q", err, expected)
+		}
+	}
+}
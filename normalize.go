@@ -0,0 +1,9 @@
+package izidic
+
+// normalize applies the normalizer set by WithNameNormalizer to a name.
+func (dic *container) normalize(name string) string {
+	if dic.normalizer == nil {
+		return name
+	}
+	return dic.normalizer(name)
+}
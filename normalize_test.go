@@ -0,0 +1,81 @@
+package izidic_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fgm/izidic"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWithNameNormalizer(t *testing.T) {
+	dic := izidic.New(izidic.WithNameNormalizer(strings.ToLower))
+	dic.Store("Prefix", "app")
+	dic.Register("S1", s1)
+	dic.Register("s2", func(dic izidic.Container) (any, error) {
+		return dic.MustParam("PREFIX").(string) + dic.MustService("s1").(string), nil
+	})
+	dic.Freeze()
+
+	if actual := dic.MustService("S2"); actual != "apps1" {
+		t.Fatalf("got %v, but expected apps1", actual)
+	}
+	if actual := dic.MustParam("prefix"); actual != "app" {
+		t.Fatalf("got %v, but expected app", actual)
+	}
+	expected := map[string][]string{
+		"paramAliases": {},
+		"params":       {"prefix"},
+		"services":     {"s1", "s2"},
+	}
+	if actual := dic.Names(); !cmp.Equal(actual, expected) {
+		t.Fatalf("unexpected names: %s", cmp.Diff(actual, expected))
+	}
+}
+
+func TestWithNameNormalizer_Analysis(t *testing.T) {
+	dic := izidic.New(izidic.WithNameNormalizer(strings.ToLower))
+	dic.Register("db", s1)
+	dic.Register("repo", dependent("repo", "DB"))
+	dic.Register("a", dependent("a", "B"))
+	dic.Register("b", dependent("b", "A"))
+	if err := izidic.CheckCycles(dic); err == nil || !strings.Contains(err.Error(), "a -> b -> a") {
+		t.Fatalf("got error %v, but expected the cycle through normalized names", err)
+	}
+
+	dic.Register("a", s1)
+	if err := dic.FreezeWithAnalysis(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]map[string]bool{
+		"b":    {"a": true},
+		"repo": {"db": true},
+	}
+	if actual := dic.DependencyMatrix(); !cmp.Equal(actual, expected) {
+		t.Fatalf("unexpected matrix: %s", cmp.Diff(actual, expected))
+	}
+}
+
+func TestWithNameNormalizer_Options(t *testing.T) {
+	r := izidic.NewRegistry()
+	r.Register("Registered", s1)
+	for _, normalizerFirst := range []bool{true, false} {
+		opts := []izidic.Option{izidic.WithInstance("Server", "srv"), izidic.WithRegistry(r), izidic.WithStrictUsage("SERVER")}
+		if normalizerFirst {
+			opts = append([]izidic.Option{izidic.WithNameNormalizer(strings.ToLower)}, opts...)
+		} else {
+			opts = append(opts, izidic.WithNameNormalizer(strings.ToLower))
+		}
+		dic := izidic.New(opts...)
+		dic.Freeze()
+		if actual, err := dic.Service("server"); err != nil || actual != "srv" {
+			t.Fatalf("normalizer first %t: got %v, %v, but expected the seeded instance", normalizerFirst, actual, err)
+		}
+		if actual, err := dic.Service("registered"); err != nil || actual != "s1" {
+			t.Fatalf("normalizer first %t: got %v, %v, but expected the registry definition", normalizerFirst, actual, err)
+		}
+		if err := dic.Close(); err != nil {
+			t.Fatalf("normalizer first %t: unexpected error: %v", normalizerFirst, err)
+		}
+	}
+}
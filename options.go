@@ -0,0 +1,226 @@
+package izidic
+
+import (
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+// Option configures a container created by New.
+type Option func(dic *container)
+
+// WithStrictUsage makes Close report the required services which were never
+// resolved during the container lifetime, to catch dead wiring which should
+// have been exercised, like a metrics pusher never started because of a bug.
+//
+// This is a development and staging aid, off by default to avoid surprising
+// production shutdowns. For an unrestricted list of unresolved services, see
+// Container.Unused.
+func WithStrictUsage(required ...string) Option {
+	return func(dic *container) {
+		dic.required = append(dic.required, required...)
+	}
+}
+
+// WithInstance seeds the container with an already-built service instance,
+// like a *http.Server created in main, so that Service(name) returns it
+// without running any factory.
+//
+// A trivial definition returning the instance is registered too,
+// so the service is listed by Names like any other.
+func WithInstance(name string, instance any) Option {
+	return func(dic *container) {
+		dic.seeds = append(dic.seeds, func() {
+			name := dic.normalize(name)
+			dic.serviceDefs[name] = func(Container) (any, error) { return instance, nil }
+			dic.services[name] = instance
+		})
+	}
+}
+
+// WithExecutionTrace wraps each service instantiation in a runtime/trace region
+// named "izidic:" followed by the service name, so that the Go execution tracer
+// shows the time spent in each service factory, revealing the startup critical path.
+//
+// Regions belong to the goroutine resolving the service. Since resolution does
+// not carry a context, they are not associated with any trace task.
+// Without this option, resolution does not touch runtime/trace at all.
+func WithExecutionTrace() Option {
+	return func(dic *container) {
+		dic.traced = true
+	}
+}
+
+// WithLockMetrics makes Service measure how long it waits to acquire the write
+// lock when caching a new instance, calling cb with that wait, to tell whether
+// lock contention slows down a concurrent startup.
+//
+// The callback runs after the lock is released, on the resolving goroutine, and
+// must be safe for concurrent use. Without this option, nothing is measured.
+func WithLockMetrics(cb func(waited time.Duration)) Option {
+	return func(dic *container) {
+		dic.lockMetrics = cb
+	}
+}
+
+// WithLockMetricsThreshold limits the calls to the WithLockMetrics callback to
+// the waits reaching threshold, to only report significant contention.
+func WithLockMetricsThreshold(threshold time.Duration) Option {
+	return func(dic *container) {
+		dic.lockThreshold = threshold
+	}
+}
+
+// WithCreationStacks makes the container capture the call stack leading to the
+// first instantiation of each service, available from Container.CreationStack,
+// to find out which code path triggered an unexpectedly eager instantiation.
+//
+// Capturing a stack is relatively costly, which is why this is off by default,
+// but it only happens once per service.
+func WithCreationStacks() Option {
+	return func(dic *container) {
+		dic.stacks = make(map[string][]runtime.Frame)
+	}
+}
+
+// WithDefaultTimeout bounds the factories of all the services registered
+// afterwards to d, like RegisterWithTimeout does, as a safety net against
+// startup hangs. Services needing longer can opt out, or use a longer timeout,
+// by registering with RegisterWithTimeout, whose duration takes precedence.
+//
+// Factories bounding their own blocking operations with a context deadline
+// remain preferable: when both apply, the earliest deadline wins, and only the
+// context one cleanly cancels the work in progress.
+//
+// As with RegisterWithTimeout, dependency cycles through bounded services are
+// reported as such, without waiting for the timeout.
+func WithDefaultTimeout(d time.Duration) Option {
+	return func(dic *container) {
+		dic.timeout = d
+	}
+}
+
+// WithStrictResolution makes a factory requesting an undefined service abort
+// the whole resolution, instead of receiving a not found error it could handle,
+// so that a mistyped dependency name fails fast during development. The Service
+// call at the top of the resolution then fails with an error naming both the
+// requesting service and the missing one.
+//
+// This applies to the Service, MustService, ServiceN, and MustServiceN calls on
+// the container passed to factories: a factory looking up an optional service
+// should check it is defined with Names first. Direct calls on the container,
+// outside factories, are not affected.
+func WithStrictResolution() Option {
+	return func(dic *container) {
+		dic.strict = true
+	}
+}
+
+// WithClock replaces the real-time clock used by the container, typically with
+// a fake one in tests, to control cache expiry, timeouts, and retry backoffs
+// without actually waiting.
+//
+// Durations measured for diagnostics, like instantiation manifests and lock
+// metrics, always use real time.
+func WithClock(c Clock) Option {
+	return func(dic *container) {
+		dic.clock = c
+	}
+}
+
+// WithResolutionBudget caps the cumulative time spent in service factories,
+// like a cold-start ceiling for serverless environments. Once the budget is
+// exceeded, further instantiations fail immediately with an error wrapping
+// ErrBudgetExceeded, naming the service whose instantiation exceeded it.
+//
+// Each factory is charged for its own time, excluding the resolution of the
+// dependencies it requests from the container it receives, which are charged
+// separately. Since the budget is only checked before running a factory, a
+// single slow factory may overshoot it before being caught: InstantiationManifest
+// tells which ones took the time.
+func WithResolutionBudget(d time.Duration) Option {
+	return func(dic *container) {
+		dic.budget = d
+	}
+}
+
+// WithWarmupConcurrency caps to n the number of factories run at the same time
+// by WarmupParallel, instead of runtime.GOMAXPROCS(0), to avoid factories
+// hammering a shared external resource, like a database, during a cold start.
+//
+// It does not limit on-demand resolution with Service. Values below 1 keep the
+// default.
+func WithWarmupConcurrency(n int) Option {
+	return func(dic *container) {
+		dic.warmup = n
+	}
+}
+
+// WithLogger sets the logger from which the loggers passed to the factories of
+// services registered by RegisterLogged are derived, instead of slog.Default.
+func WithLogger(l *slog.Logger) Option {
+	return func(dic *container) {
+		dic.logger = l
+	}
+}
+
+// WithMustAsError makes MustParam, MustService, and their N variants pass their
+// errors to handler, and return nil, instead of panicking, so that centralized
+// error handling can take over in long-running servers.
+//
+// Since the nil result usually causes a panic on a later type assertion anyway,
+// handler should typically terminate the program or the current goroutine,
+// like with log.Fatal or runtime.Goexit. Must calls in factories, on the
+// container passed to them, still panic, failing the resolution.
+func WithMustAsError(handler func(error)) Option {
+	return func(dic *container) {
+		dic.mustHandler = handler
+	}
+}
+
+// WithIntrospection(false) disables the recording of introspection data during
+// resolution, for production builds not wanting to pay for it, or to expose
+// the internal structure of the application. It then skips:
+//
+//   - instantiation timings: InstantiationManifest returns nil, and
+//     WriteFlameGraph writes nothing
+//   - creation stacks, even with WithCreationStacks: CreationStack returns nil
+//   - dependency edges: DependencyMatrix returns nil, although FreezeWithAnalysis
+//     still records them internally, to schedule WarmupParallel
+//
+// Introspection is enabled by default, and other methods, like Names, Explain,
+// Inventory, or Stats, are not affected, nor is
+// izidictest.AssertNoEagerInstantiation, which relies on Inventory.
+func WithIntrospection(enabled bool) Option {
+	return func(dic *container) {
+		dic.opaque = !enabled
+	}
+}
+
+// WithNameNormalizer applies fn to the parameter and service names given to the
+// container, both when defining and looking them up, so that names following
+// inconsistent conventions resolve to the same entry, like "DB" and "db" with
+// strings.ToLower.
+//
+// Names are stored normalized, so Names and the other introspection methods
+// return normalized forms. Since distinct names mapping to the same normalized
+// one designate the same entry, the last definition wins, silently: prefer
+// normalizers only folding variations of the same name, like case. Since names
+// may be normalized more than once, fn must be idempotent.
+//
+// Names given in other options, like WithInstance, are normalized too, whatever
+// the order of the options. Without this option, names are used as given.
+func WithNameNormalizer(fn func(string) string) Option {
+	return func(dic *container) {
+		dic.normalizer = fn
+	}
+}
+
+// WithCloseOnSwap makes Swap close the instances it drops which implement
+// io.Closer, in the reverse order of their instantiation, returning their
+// errors joined. Callers still holding these instances must no longer use them.
+func WithCloseOnSwap() Option {
+	return func(dic *container) {
+		dic.closeOnSwap = true
+	}
+}
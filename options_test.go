@@ -0,0 +1,189 @@
+package izidic_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/trace"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fgm/izidic"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWithInstance(t *testing.T) {
+	srv := &http.Server{Addr: ":8080"}
+	dic := izidic.New(izidic.WithInstance("server", srv))
+	dic.Register("addr", func(dic izidic.Container) (any, error) {
+		return dic.MustService("server").(*http.Server).Addr, nil
+	})
+	dic.Freeze()
+
+	if actual := dic.MustService("server").(*http.Server); actual != srv {
+		t.Fatalf("got %p, but expected the seeded instance %p", actual, srv)
+	}
+	if actual := dic.MustService("addr").(string); actual != srv.Addr {
+		t.Fatalf("got %q, but expected %q", actual, srv.Addr)
+	}
+	expected := []string{"addr", "server"}
+	if actual := dic.Names()["services"]; !cmp.Equal(actual, expected) {
+		t.Fatalf("unexpected services: %s", cmp.Diff(actual, expected))
+	}
+}
+
+func TestWithExecutionTrace(t *testing.T) {
+	if trace.IsEnabled() {
+		t.Skip("execution tracer already running")
+	}
+	buf := bytes.Buffer{}
+	if err := trace.Start(&buf); err != nil {
+		t.Fatalf("failed starting trace: %v", err)
+	}
+	dic := izidic.New(izidic.WithExecutionTrace())
+	dic.Register("traced-service", s1)
+	dic.MustService("traced-service")
+	trace.Stop()
+
+	if !bytes.Contains(buf.Bytes(), []byte("izidic:traced-service")) {
+		t.Fatal("trace does not contain the service region")
+	}
+}
+
+func TestWithStrictUsage(t *testing.T) {
+	dic := izidic.New(izidic.WithStrictUsage("s1", "s2", "s3"))
+	dic.Register("s1", s1)
+	dic.Register("s2", s2)
+	dic.Register("s3", s1)
+	dic.Freeze()
+	dic.MustService("s2")
+
+	const expected = "required services never resolved: s3"
+	if err := dic.Close(); err == nil || err.Error() != expected {
+		t.Fatalf("got error %v, but expected %q", err, expected)
+	}
+	if actual := dic.State(); actual != izidic.Closed {
+		t.Fatalf("got state %s, but expected %s", actual, izidic.Closed)
+	}
+}
+
+func TestWithLockMetrics(t *testing.T) {
+	var waits []time.Duration
+	dic := izidic.New(izidic.WithLockMetrics(func(waited time.Duration) {
+		waits = append(waits, waited)
+	}))
+	dic.Register("s1", s1)
+	dic.Register("s2", s2)
+	dic.Freeze()
+
+	dic.MustService("s2")
+	dic.MustService("s2")
+	// s2 resolves s1, so both are cached once, and the second resolution reuses s2.
+	if len(waits) != 2 {
+		t.Fatalf("got %d lock waits reported, but expected 2", len(waits))
+	}
+
+	waits = nil
+	dic = izidic.New(izidic.WithLockMetricsThreshold(time.Hour), izidic.WithLockMetrics(func(waited time.Duration) {
+		waits = append(waits, waited)
+	}))
+	dic.Register("s1", s1)
+	dic.Freeze()
+	dic.MustService("s1")
+	if len(waits) != 0 {
+		t.Fatalf("got %d lock waits reported below threshold, but expected none", len(waits))
+	}
+}
+
+func TestWithWarmupConcurrency(t *testing.T) {
+	const limit = 2
+	var mu sync.Mutex
+	running, peak := 0, 0
+	// counting tracks the peak number of factories running at the same time.
+	counting := func(izidic.Container) (any, error) {
+		mu.Lock()
+		running++
+		if running > peak {
+			peak = running
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return struct{}{}, nil
+	}
+	dic := izidic.New(izidic.WithWarmupConcurrency(limit))
+	for i := 0; i < 3*limit; i++ {
+		dic.Register(fmt.Sprintf("s%d", i), counting)
+	}
+	if err := dic.FreezeWithAnalysis(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	peak = 0 // Ignore discovery.
+
+	if err := dic.WarmupParallel(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if peak > limit {
+		t.Fatalf("got %d factories running at the same time, but expected at most %d", peak, limit)
+	}
+}
+
+func TestWithMustAsError(t *testing.T) {
+	var errs []error
+	dic := izidic.New(izidic.WithMustAsError(func(err error) {
+		errs = append(errs, err)
+	}))
+	dic.Store("p1", "v1")
+	dic.Register("s1", s1)
+	dic.Freeze()
+
+	if actual := dic.MustService("missing"); actual != nil {
+		t.Fatalf("got %v, but expected nil", actual)
+	}
+	if actual := dic.MustParamN("missing"); actual != nil {
+		t.Fatalf("got %v, but expected nil", actual)
+	}
+	if actual := dic.MustService("s1"); actual != "s1" {
+		t.Fatalf("got %v, but expected s1", actual)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("got %d errors handled, but expected 2: %v", len(errs), errs)
+	}
+}
+
+func TestWithIntrospection(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		t.Run(fmt.Sprintf("enabled %t", enabled), func(t *testing.T) {
+			dic := izidic.New(izidic.WithCreationStacks(), izidic.WithIntrospection(enabled))
+			dic.Register("s1", s1)
+			dic.Register("s2", s2)
+			if err := dic.FreezeWithAnalysis(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			dic.MustService("s2")
+
+			buf := bytes.Buffer{}
+			if err := dic.WriteFlameGraph(&buf); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			recorded := [...]bool{
+				len(dic.InstantiationManifest()) > 0,
+				buf.Len() > 0,
+				dic.CreationStack("s2") != nil,
+				dic.DependencyMatrix() != nil,
+			}
+			for i, actual := range recorded {
+				if actual != enabled {
+					t.Errorf("got %t for introspection data %d, but expected %t", actual, i, enabled)
+				}
+			}
+			if err := dic.WarmupParallel(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
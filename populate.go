@@ -0,0 +1,67 @@
+package izidic
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Populate assigns services to the fields of the struct dst points to, for the
+// fields tagged with the name of the service to inject, like:
+//
+//	type Handler struct {
+//		Logger *log.Logger `izidic:"logger"`
+//		DB     *sql.DB     `izidic:"db"`
+//	}
+//
+//	var h Handler
+//	err := izidic.Populate(dic, &h)
+//
+// Fields of embedded structs, and of non-nil pointers to embedded structs, are
+// populated too. Untagged fields are left untouched.
+//
+// Since unexported fields cannot be assigned, tagging one is an error.
+// Errors are reported for each failing field, joined in field order, and do not
+// prevent the other fields from being populated.
+func Populate(dic Container, dst any) error {
+	ptr := reflect.ValueOf(dst)
+	if ptr.Kind() != reflect.Pointer || ptr.IsNil() || ptr.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("populate target must be a non-nil pointer to a struct, not %T", dst)
+	}
+	return populate(dic, ptr.Elem())
+}
+
+func populate(dic Container, v reflect.Value) error {
+	var errs []error
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, fv := t.Field(i), v.Field(i)
+		name, tagged := field.Tag.Lookup("izidic")
+		if !tagged {
+			if !field.Anonymous {
+				continue
+			}
+			if fv.Kind() == reflect.Pointer && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				if err := populate(dic, fv); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			continue
+		}
+		if !fv.CanSet() {
+			errs = append(errs, fmt.Errorf("field %s.%s: cannot inject service %q into unexported field", t, field.Name, name))
+			continue
+		}
+		instance, err := dic.Service(name)
+		if err == nil {
+			err = assign(name, instance, fv)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("field %s.%s: %w", t, field.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,99 @@
+package izidic_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+type Logging struct {
+	Logger *log.Logger `izidic:"logger"`
+}
+
+type Output struct {
+	Writer io.Writer `izidic:"writer"`
+}
+
+type handler struct {
+	Logging
+	*Output
+	Name    string `izidic:"name"`
+	Ignored int
+}
+
+func TestPopulate(t *testing.T) {
+	dic := izidic.New()
+	dic.Register("logger", func(izidic.Container) (any, error) {
+		return log.New(&bytes.Buffer{}, "", 0), nil
+	})
+	dic.Register("writer", func(izidic.Container) (any, error) {
+		return &bytes.Buffer{}, nil
+	})
+	dic.Register("name", func(izidic.Container) (any, error) {
+		return "h", nil
+	})
+	dic.Freeze()
+
+	h := handler{Output: &Output{}, Ignored: 42}
+	if err := izidic.Populate(dic, &h); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h.Logger != dic.MustService("logger") {
+		t.Fatalf("got logger %p, but expected the service instance", h.Logger)
+	}
+	if h.Writer != dic.MustService("writer") {
+		t.Fatalf("got writer %p, but expected the service instance", h.Writer)
+	}
+	if h.Name != "h" || h.Ignored != 42 {
+		t.Fatalf("got name %q and ignored %d, but expected \"h\" and 42", h.Name, h.Ignored)
+	}
+
+	// Nil embedded pointers are skipped.
+	h = handler{}
+	if err := izidic.Populate(dic, &h); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h.Output != nil {
+		t.Fatalf("got output %v, but expected it to remain nil", h.Output)
+	}
+}
+
+type unexportedDeps struct {
+	name string `izidic:"name"`
+}
+
+type mismatchDeps struct {
+	Name    int    `izidic:"name"`
+	Missing string `izidic:"missing"`
+}
+
+func TestPopulate_Errors(t *testing.T) {
+	dic := izidic.New()
+	dic.Register("name", func(izidic.Container) (any, error) {
+		return "h", nil
+	})
+	dic.Freeze()
+
+	tests := [...]struct {
+		name     string
+		dst      any
+		expected string
+	}{
+		{"not pointer", mismatchDeps{}, "populate target must be a non-nil pointer to a struct, not izidic_test.mismatchDeps"},
+		{"not struct", new(string), "populate target must be a non-nil pointer to a struct, not *string"},
+		{"unexported", &unexportedDeps{}, `field izidic_test.unexportedDeps.name: cannot inject service "name" into unexported field`},
+		{"each field", &mismatchDeps{}, `field izidic_test.mismatchDeps.Name: service "name" has type string, not assignable to int` + "\n" +
+			`field izidic_test.mismatchDeps.Missing: service not found: "missing"`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := izidic.Populate(dic, test.dst); fmt.Sprint(err) != test.expected {
+				t.Fatalf("got error %v, but expected %q", err, test.expected)
+			}
+		})
+	}
+}
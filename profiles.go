@@ -0,0 +1,65 @@
+package izidic
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SetProfile activates a profile, like "dev", "test", or "prod", selecting the
+// services registered with RegisterProfile for it, so that the same wiring code
+// can register in-memory stubs for tests and real clients for production.
+//
+// It must be called before any RegisterProfile call, and panics otherwise.
+func (dic *container) SetProfile(name string) {
+	dic.Lock()
+	defer dic.Unlock()
+	dic.mustBuild("set profile")
+	if len(dic.profiles) > 0 {
+		panic(fmt.Sprintf("Cannot set profile %s after registering profile services", name))
+	}
+	dic.profile = name
+}
+
+// RegisterProfile registers a service for a profile. If the profile is active,
+// the service is registered as with Register, and takes precedence over any
+// definition without a profile for the same name, whatever the registration
+// order: these act as defaults for the services not defined for the active profile.
+// Otherwise, the definition is discarded.
+//
+// Resolving a service only defined for inactive profiles fails with an error
+// listing these profiles.
+func (dic *container) RegisterProfile(profile, name string, fn Service) {
+	name = dic.normalize(name)
+	mustFactory(name, fn)
+	dic.Lock()
+	dic.mustBuild("register services")
+	dic.profiles[name] = append(dic.profiles[name], profile)
+	active := profile == dic.profile
+	timeout := dic.timeout
+	if active {
+		dic.overrides[name] = false // Let define store it.
+	}
+	dic.Unlock()
+	if !active {
+		return
+	}
+
+	dic.define(name, fn, nil, timeout)
+	dic.Lock()
+	defer dic.Unlock()
+	dic.overrides[name] = true
+}
+
+// profileNotFound returns the error for a service only defined for inactive
+// profiles, or nil if it is not.
+//
+// Callers must hold the lock.
+func (dic *container) profileNotFound(name string) error {
+	profiles := dic.profiles[name]
+	if len(profiles) == 0 {
+		return nil
+	}
+	profiles = append([]string(nil), profiles...)
+	sort.Strings(profiles)
+	return fmt.Errorf("service not found in profile %q: %q is only defined for profiles %q", dic.profile, name, profiles)
+}
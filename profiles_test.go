@@ -0,0 +1,98 @@
+package izidic_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fgm/izidic"
+)
+
+func TestContainer_RegisterProfile(t *testing.T) {
+	// constant builds a service returning a fixed value.
+	constant := func(v string) izidic.Service {
+		return func(izidic.Container) (any, error) { return v, nil }
+	}
+	// wire is the same registration code for all profiles.
+	wire := func(dic izidic.Container) {
+		dic.RegisterProfile("test", "db", constant("memory"))
+		dic.Register("db", constant("default")) // Registered later, but still a default.
+		dic.RegisterProfile("prod", "db", constant("postgres"))
+		dic.Register("logger", constant("stderr"))
+		dic.RegisterProfile("prod", "metrics", constant("prometheus"))
+	}
+
+	tests := [...]struct {
+		profile string
+		db      string
+		metrics string
+	}{
+		{"test", "memory", `service not found in profile "test": "metrics" is only defined for profiles ["prod"]`},
+		{"prod", "postgres", "prometheus"},
+		{"", "default", `service not found in profile "": "metrics" is only defined for profiles ["prod"]`},
+	}
+	for _, test := range tests {
+		t.Run(test.profile, func(t *testing.T) {
+			dic := izidic.New()
+			dic.SetProfile(test.profile)
+			wire(dic)
+			dic.Freeze()
+
+			if actual := dic.MustService("db"); actual != test.db {
+				t.Fatalf("got db %v, but expected %s", actual, test.db)
+			}
+			if actual := dic.MustService("logger"); actual != "stderr" {
+				t.Fatalf("got logger %v, but expected the default", actual)
+			}
+			metrics, err := dic.Service("metrics")
+			if err != nil {
+				metrics = err.Error()
+			}
+			if fmt.Sprint(metrics) != test.metrics {
+				t.Fatalf("got metrics %v, but expected %s", metrics, test.metrics)
+			}
+		})
+	}
+
+	defer func() {
+		const expected = "Cannot set profile prod after registering profile services"
+		if rec := recover(); fmt.Sprint(rec) != expected {
+			t.Fatalf("got panic %v, but expected %s", rec, expected)
+		}
+	}()
+	dic := izidic.New()
+	wire(dic)
+	dic.SetProfile("prod")
+}
+
+func TestContainer_RegisterProfile_SideTables(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	builds := 0
+	dic := izidic.New(izidic.WithClock(clock))
+	dic.SetProfile("test")
+	dic.RegisterProfile("test", "cache", func(izidic.Container) (any, error) {
+		builds++
+		return builds, nil
+	})
+	dic.RegisterProfile("test", "client", func(izidic.Container) (any, error) { return nil, nil })
+	dic.RegisterProfile("test", "api", func(izidic.Container) (any, error) { return "stub", nil })
+	dic.RegisterCached("cache", s1, time.Second)
+	dic.RegisterNilable("client", s1)
+	dic.RegisterVersioned("api", "1.0.0", s1)
+	dic.Freeze()
+
+	dic.MustService("cache")
+	clock.Advance(time.Minute)
+	if actual := dic.MustService("cache"); actual != 1 {
+		t.Fatalf("got build %v, but expected the profile service not to expire", actual)
+	}
+	if _, err := dic.Service("client"); err == nil {
+		t.Fatal("expected the profile service not to be nilable")
+	}
+	if actual := dic.MustService("api"); actual != "stub" {
+		t.Fatalf("got %v, but expected the profile service", actual)
+	}
+	if _, err := dic.ServiceVersion("api", "1.0.0"); err == nil {
+		t.Fatal("expected the version to be discarded for the profile service")
+	}
+}
@@ -0,0 +1,37 @@
+package izidic
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MustBeReady panics unless the container is ready to serve, as a production
+// tripwire against serving a container still in build mode or with missing
+// wiring. It checks, joining the errors of all failed checks in that order:
+//
+//  1. the container is Frozen
+//  2. Validate, checking declared parameters, including required ones
+//  3. the services required by WithStrictUsage are registered
+//  4. CheckCycles, checking for dependency cycles in a throwaway copy
+//
+// Since CheckCycles runs all factories in a throwaway copy, this is meant for
+// a one-time assertion at startup, like before starting a server, not for use
+// on each request.
+func (dic *container) MustBeReady() {
+	var errs []error
+	if state := dic.State(); state != Frozen {
+		errs = append(errs, fmt.Errorf("container is %s, not %s", state, Frozen))
+	}
+	errs = append(errs, dic.Validate())
+	dic.RLock()
+	for _, name := range dic.required {
+		if _, found := dic.serviceDefs[name]; !found {
+			errs = append(errs, fmt.Errorf("required service %q: not registered", name))
+		}
+	}
+	dic.RUnlock()
+	errs = append(errs, CheckCycles(dic))
+	if err := errors.Join(errs...); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,65 @@
+package izidic_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+func TestContainer_MustBeReady(t *testing.T) {
+	checks := [...]struct {
+		name     string
+		setup    func() izidic.Container
+		expected string // Empty if ready.
+	}{
+		{"ready", func() izidic.Container {
+			dic := izidic.New(izidic.WithStrictUsage("s2"))
+			dic.Register("s1", s1)
+			dic.Register("s2", s2)
+			dic.Freeze()
+			return dic
+		}, ""},
+		{"building", func() izidic.Container {
+			dic := izidic.New()
+			dic.Register("s1", s1)
+			return dic
+		}, "container is building, not frozen"},
+		{"missing param", func() izidic.Container {
+			dic := izidic.New()
+			dic.DeclareParam("port", izidic.ParamSpec{Required: true})
+			return dic // Freeze would panic too.
+		}, `parameter "port": required but not stored`},
+		{"missing service", func() izidic.Container {
+			dic := izidic.New(izidic.WithStrictUsage("s3"))
+			dic.Register("s1", s1)
+			dic.Freeze()
+			return dic
+		}, `required service "s3": not registered`},
+		{"cycle", func() izidic.Container {
+			dic := izidic.New()
+			dic.Register("a", dependent("a", "b"))
+			dic.Register("b", dependent("b", "a"))
+			dic.Freeze()
+			return dic
+		}, "circular dependency detected"},
+	}
+	for _, check := range checks {
+		t.Run(check.name, func(t *testing.T) {
+			dic := check.setup()
+			defer func() {
+				rec := recover()
+				switch {
+				case check.expected == "" && rec != nil:
+					t.Fatalf("unexpected panic: %v", rec)
+				case check.expected != "" && rec == nil:
+					t.Fatalf("expected a panic containing %q", check.expected)
+				case rec != nil && !strings.Contains(fmt.Sprint(rec), check.expected):
+					t.Fatalf("got panic %v, but expected it to contain %q", rec, check.expected)
+				}
+			}()
+			dic.MustBeReady()
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package izidic
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Registry is a set of service definitions contributed by independent packages,
+// typically from their init functions, to seed containers with WithRegistry,
+// the way database/sql drivers register themselves.
+type Registry struct {
+	mu   sync.Mutex
+	defs map[string][]registration
+}
+
+// registration is a service definition in a Registry, with the location of the
+// Register call, to report collisions.
+type registration struct {
+	fn     Service
+	origin string
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{defs: make(map[string][]registration)}
+}
+
+// Register adds a service definition to the registry. It is safe for concurrent use.
+//
+// Registering the same name twice is not an error here, but panics in the
+// containers created from the registry, since they cannot choose one.
+// Registering a nil factory panics immediately, like Container.Register.
+func (r *Registry) Register(name string, fn Service) {
+	r.register(name, fn, 2)
+}
+
+func (r *Registry) register(name string, fn Service, skip int) {
+	mustFactory(name, fn)
+	origin := "unknown location"
+	if _, file, line, ok := runtime.Caller(skip); ok {
+		origin = fmt.Sprintf("%s:%d", file, line)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.defs[name] = append(r.defs[name], registration{fn: fn, origin: origin})
+}
+
+// DefaultRegistry is the process-wide registry to which Register adds definitions.
+var DefaultRegistry = NewRegistry()
+
+// Register adds a service definition to DefaultRegistry, for packages to
+// contribute services from their init functions without a central wiring file:
+//
+//	func init() {
+//		izidic.Register("mailer", newMailer)
+//	}
+//
+// Containers only use these definitions when created with
+// WithRegistry(DefaultRegistry), so explicit wiring remains the default.
+//
+// Go runs the init functions of a package after those of the packages it
+// imports, but the order between unrelated packages is not something to rely
+// on: definitions must not depend on each other at registration time, and
+// WithRegistry must only be used once all contributing packages are
+// initialized, i.e. not from an init function.
+func Register(name string, fn Service) {
+	DefaultRegistry.register(name, fn, 2)
+}
+
+// WithRegistry seeds the container with the service definitions in a registry,
+// which later Register calls on the container may replace.
+//
+// Definitions are registered once all options are applied, so they are bounded
+// by the default timeout set by WithDefaultTimeout, whatever the order of the
+// options. Since no interceptor can be set before New returns, they are the
+// only definitions SetRegisterInterceptor never applies to.
+//
+// It panics, making New panic, if the registry holds several definitions for
+// the same name, reporting where they were registered.
+func WithRegistry(r *Registry) Option {
+	return func(dic *container) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		var collisions []string
+		defs := make(map[string]Service, len(r.defs))
+		for name, regs := range r.defs {
+			if len(regs) > 1 {
+				origins := make([]string, len(regs))
+				for i, reg := range regs {
+					origins[i] = reg.origin
+				}
+				collisions = append(collisions, fmt.Sprintf("%q at %s", name, strings.Join(origins, ", ")))
+				continue
+			}
+			defs[name] = regs[0].fn
+		}
+		if len(collisions) > 0 {
+			sort.Strings(collisions)
+			panic(fmt.Sprintf("Cannot seed container with services registered several times: %s",
+				strings.Join(collisions, "; ")))
+		}
+		dic.seeds = append(dic.seeds, func() {
+			for name, fn := range defs {
+				dic.register(dic.normalize(name), fn, nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package izidic_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/fgm/izidic"
+)
+
+func init() {
+	izidic.Register("registered", s1)
+}
+
+func TestRegister(t *testing.T) {
+	dic := izidic.New(izidic.WithRegistry(izidic.DefaultRegistry))
+	dic.Register("s2", func(dic izidic.Container) (any, error) {
+		return dic.MustService("registered").(string) + "s2", nil
+	})
+	dic.Freeze()
+	if actual := dic.MustService("s2"); actual != "s1s2" {
+		t.Fatalf("got %v, but expected s1s2", actual)
+	}
+
+	if _, err := izidic.New().Service("registered"); err == nil {
+		t.Fatal("expected containers without WithRegistry to ignore the registry")
+	}
+}
+
+func TestWithRegistry_Collision(t *testing.T) {
+	r := izidic.NewRegistry()
+	r.Register("s1", s1)
+	r.Register("s2", s2)
+	r.Register("s1", s2)
+
+	expected := regexp.MustCompile(`^Cannot seed container with services registered several times: "s1" at .*registry_test.go:\d+, .*registry_test.go:\d+$`)
+	defer func() {
+		if rec := recover(); !expected.MatchString(fmt.Sprint(rec)) {
+			t.Fatalf("got panic %v, but expected %s", rec, expected)
+		}
+	}()
+	izidic.New(izidic.WithRegistry(r))
+}
+
+func TestRegistry_Register_NilFactory(t *testing.T) {
+	defer func() {
+		const expected = `cannot register nil factory for "nil"`
+		if rec := recover(); rec != expected {
+			t.Fatalf("got panic %#v, but expected %q", rec, expected)
+		}
+	}()
+	izidic.NewRegistry().Register("nil", nil)
+}
+
+func TestWithRegistry_DefaultTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	r := izidic.NewRegistry()
+	r.Register("stuck", func(izidic.Container) (any, error) {
+		<-release
+		return nil, nil
+	})
+	dic := izidic.New(izidic.WithRegistry(r), izidic.WithDefaultTimeout(time.Millisecond))
+	dic.Freeze()
+	if _, err := dic.Service("stuck"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v, but expected a timeout", err)
+	}
+}
@@ -0,0 +1,16 @@
+package izidic
+
+// Remove marks a parameter or service name as removed in version since, like
+// "v2.0.0", so that looking it up fails with an error like `"x" was removed in
+// v2.0.0; see migration notes`, instead of a plain not found error, as a clearer
+// migration signal for the final phase of a deprecation.
+//
+// Removed names are only consulted when a lookup misses, so defining the name
+// again makes it resolvable as usual.
+func (dic *container) Remove(name string, since string) {
+	name = dic.normalize(name)
+	dic.Lock()
+	defer dic.Unlock()
+	dic.mustBuild("remove names")
+	dic.removed[name] = since
+}
@@ -0,0 +1,28 @@
+package izidic_test
+
+import (
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+func TestContainer_Remove(t *testing.T) {
+	dic := izidic.New()
+	dic.Remove("legacy-db", "v2.0.0")
+	dic.Remove("legacy-port", "v2.1.0")
+	dic.Remove("s1", "v2.0.0")
+	dic.Register("s1", s1) // Defined again.
+	dic.Freeze()
+
+	const expectedService = `"legacy-db" was removed in v2.0.0; see migration notes`
+	if _, err := dic.Service("legacy-db"); err == nil || err.Error() != expectedService {
+		t.Fatalf("got error %v, but expected %q", err, expectedService)
+	}
+	const expectedParam = `"legacy-port" was removed in v2.1.0; see migration notes`
+	if _, err := dic.Param("legacy-port"); err == nil || err.Error() != expectedParam {
+		t.Fatalf("got error %v, but expected %q", err, expectedParam)
+	}
+	if _, err := dic.Service("s1"); err != nil {
+		t.Fatalf("unexpected error for a name defined again: %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package izidic
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Redacted replaces the values of secret parameters in reports.
+const Redacted = "***"
+
+// ParamInfo describes a parameter for display, like in a configuration dump.
+type ParamInfo struct {
+	Name  string
+	Type  string // The dynamic type of the value, like "int" or "*url.URL".
+	Value string // The value formatted with %v, or Redacted for secrets.
+}
+
+// MarkSecret marks a parameter as secret, so that ParamReport redacts its value.
+//
+// Parameters may be marked before or after being stored.
+func (dic *container) MarkSecret(name string) {
+	name = dic.normalize(name)
+	dic.Lock()
+	defer dic.Unlock()
+	dic.mustBuild("mark secrets")
+	dic.secrets[name] = true
+}
+
+// StoreSecret stores a parameter holding a secret, like a password or an API key,
+// marking it as MarkSecret does. It remains available as usual to Param and
+// MustParam, for actual use.
+//
+// No introspection output reveals secret values: ParamReport and the errors for
+// values violating StoreEnum constraints show them as Redacted, while Names,
+// NamesCached, Diff, and the izidicdebug reports only include parameter names
+// and types, never values, for any parameter.
+func (dic *container) StoreSecret(name string, v any) {
+	name = dic.normalize(name)
+	dic.Lock()
+	defer dic.Unlock()
+	dic.mustBuild("store parameters")
+	dic.secrets[name] = true // Marked first, so that enum violations do not reveal the value.
+	dic.mustEnum(name, v)
+	dic.ownParams()
+	dic.parameters[name] = v
+	delete(dic.paramAliases, name)
+	delete(dic.paramServices, name)
+	dic.names = nil
+}
+
+// ParamReport describes all the parameters stored on the container, sorted by
+// name, with the values of those marked by MarkSecret redacted, for a safe
+// operational view of the configuration like a /debug/config endpoint.
+//
+// It does not resolve any service.
+func (dic *container) ParamReport() []ParamInfo {
+	dic.RLock()
+	defer dic.RUnlock()
+	report := make([]ParamInfo, 0, len(dic.parameters))
+	for name, value := range dic.parameters {
+		info := ParamInfo{Name: name, Type: fmt.Sprintf("%T", value), Value: Redacted}
+		if !dic.secrets[name] {
+			info.Value = fmt.Sprintf("%v", value)
+		}
+		report = append(report, info)
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Name < report[j].Name })
+	return report
+}
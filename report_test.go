@@ -0,0 +1,35 @@
+package izidic_test
+
+import (
+	"testing"
+
+	"github.com/fgm/izidic"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestContainer_ParamReport(t *testing.T) {
+	dic := izidic.New()
+	dic.MarkSecret("password")
+	dic.Store("port", 8080)
+	dic.Store("password", "hunter2")
+	dic.StoreSecret("token", "s3cr3t")
+	dic.Store("hosts", []string{"a", "b"})
+	dic.Register("s1", s1)
+	dic.Freeze()
+
+	expected := []izidic.ParamInfo{
+		{Name: "hosts", Type: "[]string", Value: "[a b]"},
+		{Name: "password", Type: "string", Value: izidic.Redacted},
+		{Name: "port", Type: "int", Value: "8080"},
+		{Name: "token", Type: "string", Value: izidic.Redacted},
+	}
+	if actual := dic.ParamReport(); !cmp.Equal(actual, expected) {
+		t.Fatalf("unexpected report: %s", cmp.Diff(actual, expected))
+	}
+	if actual := dic.MustParam("password"); actual != "hunter2" {
+		t.Fatalf("got %v, but expected the secret value", actual)
+	}
+	if actual := dic.MustParam("token"); actual != "s3cr3t" {
+		t.Fatalf("got %v, but expected the secret value", actual)
+	}
+}
@@ -0,0 +1,47 @@
+package izidic
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Result holds the outcome of a Try: either a typed service instance, or an error.
+//
+// It is an alternative ergonomics layer for code preferring neither panics
+// like MustService, nor multiple return values like Service.
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+// Value returns the service instance, or the zero value of T on error.
+func (r Result[T]) Value() T {
+	return r.value
+}
+
+// Err returns the error which occurred during resolution, if any.
+func (r Result[T]) Err() error {
+	return r.err
+}
+
+// Or returns the service instance, or def on error.
+func (r Result[T]) Or(def T) T {
+	if r.err != nil {
+		return def
+	}
+	return r.value
+}
+
+// Try resolves a service and type-asserts it to T, wrapping the outcome in a Result.
+func Try[T any](dic Container, name string) Result[T] {
+	instance, err := dic.Service(name)
+	if err != nil {
+		return Result[T]{err: err}
+	}
+	value, ok := instance.(T)
+	if !ok {
+		return Result[T]{err: fmt.Errorf("service %q has type %T, not %s",
+			name, instance, reflect.TypeOf((*T)(nil)).Elem())}
+	}
+	return Result[T]{value: value}
+}
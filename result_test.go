@@ -0,0 +1,47 @@
+package izidic_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+func TestTry(t *testing.T) {
+	dic := izidic.New()
+	dic.Register("s1", s1)
+	dic.Freeze()
+
+	tests := [...]struct {
+		name        string
+		result      izidic.Result[string]
+		expected    string
+		expectedErr string
+	}{
+		{"happy", izidic.Try[string](dic, "s1"), "s1", ""},
+		{"missing", izidic.Try[string](dic, "k2"), "", fmt.Sprintf("service not found: %q", "k2")},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := test.result.Value(); actual != test.expected {
+				t.Errorf("got %q, but expected %q", actual, test.expected)
+			}
+			if actual := test.result.Or("def"); test.expectedErr != "" && actual != "def" {
+				t.Errorf("got %q, but expected default", actual)
+			}
+			err := test.result.Err()
+			if test.expectedErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if test.expectedErr != "" && (err == nil || err.Error() != test.expectedErr) {
+				t.Errorf("got error %v, but expected %q", err, test.expectedErr)
+			}
+		})
+	}
+
+	res := izidic.Try[fmt.Stringer](dic, "s1")
+	const expected = `service "s1" has type string, not fmt.Stringer`
+	if res.Err() == nil || res.Err().Error() != expected {
+		t.Fatalf("got error %v, but expected %q", res.Err(), expected)
+	}
+}
@@ -0,0 +1,33 @@
+package izidic
+
+import (
+	"fmt"
+	"time"
+)
+
+// RegisterWithRetry registers a service whose factory is retried on failure,
+// up to attempts times in total, waiting for backoff between attempts.
+//
+// This suits services wrapping external resources which may not be ready yet,
+// like a database still booting. The error returned after the last attempt
+// wraps the last failure.
+func (dic *container) RegisterWithRetry(name string, fn Service, attempts int, backoff time.Duration) {
+	name = dic.normalize(name)
+	mustFactory(name, fn)
+	if attempts < 1 {
+		attempts = 1
+	}
+	dic.Register(name, func(dic Container) (any, error) {
+		var err error
+		for attempt := 1; attempt <= attempts; attempt++ {
+			var instance any
+			if instance, err = fn(dic); err == nil {
+				return instance, nil
+			}
+			if attempt < attempts {
+				<-dic.Clock().After(backoff)
+			}
+		}
+		return nil, fmt.Errorf("giving up after %d attempts: %w", attempts, err)
+	})
+}
@@ -0,0 +1,58 @@
+package izidic_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fgm/izidic"
+)
+
+func TestContainer_RegisterWithRetry(t *testing.T) {
+	errNotReady := errors.New("not ready")
+	// flaky builds a service failing until its n-th call.
+	flaky := func(n int) (izidic.Service, *int) {
+		calls := 0
+		return func(izidic.Container) (any, error) {
+			calls++
+			if calls < n {
+				return nil, errNotReady
+			}
+			return calls, nil
+		}, &calls
+	}
+
+	tests := [...]struct {
+		name          string
+		succeedAt     int
+		attempts      int
+		expectedCalls int
+		expectedErr   string
+	}{
+		{"first", 1, 3, 1, ""},
+		{"last", 3, 3, 3, ""},
+		{"exhausted", 5, 3, 3, "failed instantiating service s: giving up after 3 attempts: not ready"},
+		{"no attempts", 1, 0, 1, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fn, calls := flaky(test.succeedAt)
+			dic := izidic.New()
+			dic.RegisterWithRetry("s", fn, test.attempts, time.Millisecond)
+			_, err := dic.Service("s")
+			if *calls != test.expectedCalls {
+				t.Errorf("got %d calls, but expected %d", *calls, test.expectedCalls)
+			}
+			if actual := fmt.Sprint(err); test.expectedErr != "" && actual != test.expectedErr {
+				t.Errorf("got error %q, but expected %q", actual, test.expectedErr)
+			}
+			if test.expectedErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if test.expectedErr != "" && !errors.Is(err, errNotReady) {
+				t.Errorf("error %v does not wrap the last failure", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package izidic
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// WithContext returns a scope whose lifetime is tied to ctx, like a per-request
+// container in an HTTP handler: once ctx is done, the scope closes, and the
+// instances it built which implement io.Closer are closed, in the reverse order
+// of their instantiation, so dependents close before their dependencies. This
+// includes instances replaced by Invalidate or Rebuild, which callers may
+// still hold.
+//
+// The scope is a frozen container sharing the definitions and parameters of the
+// container, and starting with its instances, which it never closes: warm up
+// the services to share across scopes, like a database pool, in the container
+// before creating scopes. Other services are instantiated in, and owned by,
+// the scope on first use.
+//
+// Closing the scope explicitly with Close cleans it up immediately, returning
+// the errors of the io.Closer instances. Cleanup happens only once, whichever
+// comes first, and errors from a cleanup triggered by ctx are returned by a
+// later Close, so that a deferred Close is always safe. Only closing the scope
+// twice is an error.
+func (dic *container) WithContext(ctx context.Context) Container {
+	c := baseOf(dic.CloneShared())
+	dic.RLock()
+	for name, instance := range dic.services {
+		c.services[name] = instance
+	}
+	c.built = copyMap(dic.built)
+	dic.RUnlock()
+	// The services required by WithStrictUsage are those of the container.
+	c.state, c.scoped, c.required = Frozen, true, nil
+
+	s := &scope{Container: c}
+	s.stop = context.AfterFunc(ctx, func() { s.cleanup() })
+	return s
+}
+
+// scope is the Container returned by WithContext.
+type scope struct {
+	Container
+	once   sync.Once
+	closed atomic.Bool // Set by Close.
+	stop   func() bool // Stops watching the context.
+	err    error       // Errors closing the owned instances.
+}
+
+func (s *scope) base() *container {
+	return baseOf(s.Container)
+}
+
+// Close needs no more than cleanup, since the scope has neither required
+// services nor acquired instances.
+func (s *scope) Close() error {
+	if s.closed.Swap(true) {
+		return errors.New("container is already closed")
+	}
+	s.stop()
+	return s.cleanup()
+}
+
+// cleanup closes the scope and the instances it owns, once.
+func (s *scope) cleanup() error {
+	s.once.Do(func() {
+		c := s.base()
+		c.Lock()
+		c.state = Closed
+		owned := c.owned
+		c.owned = nil
+		c.Unlock()
+		// Closers are user code, so they must not run while holding the lock.
+		s.err = closeAcquired(owned)
+	})
+	return s.err
+}
+
+// ownInstance records an instance built by a scope, to close it with the scope,
+// including instances replaced since, like after Invalidate or Rebuild.
+//
+// Callers must hold the lock.
+func (dic *container) ownInstance(name string, instance any) {
+	closer, ok := instance.(io.Closer)
+	if !dic.scoped || !ok || isAcquired(dic.owned, closer) {
+		return
+	}
+	dic.owned = append(dic.owned, acquisition{name: name, closer: closer})
+}
@@ -0,0 +1,185 @@
+package izidic_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fgm/izidic"
+	"github.com/google/go-cmp/cmp"
+)
+
+// closer records its closing in a shared log.
+type closer struct {
+	name string
+	mu   *sync.Mutex
+	log  *[]string
+	err  error
+}
+
+func (c *closer) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	*c.log = append(*c.log, c.name)
+	return c.err
+}
+
+func TestContainer_WithContext(t *testing.T) {
+	errFailed := errors.New("failed")
+	mu := sync.Mutex{}
+	var closed []string
+	newCloser := func(name string, err error, deps ...string) izidic.Service {
+		return func(dic izidic.Container) (any, error) {
+			for _, dep := range deps {
+				if _, err := dic.Service(dep); err != nil {
+					return nil, err
+				}
+			}
+			return &closer{name: name, mu: &mu, log: &closed, err: err}, nil
+		}
+	}
+	dic := izidic.New()
+	dic.Register("pool", newCloser("pool", nil))
+	dic.Register("tx", newCloser("tx", nil, "pool"))
+	dic.Register("repo", newCloser("repo", errFailed, "tx"))
+	dic.Freeze()
+	pool := dic.MustService("pool")
+
+	t.Run("cancelled", func(t *testing.T) {
+		closed = nil
+		ctx, cancel := context.WithCancel(context.Background())
+		scope := dic.WithContext(ctx)
+		if actual := scope.MustService("pool"); actual != pool {
+			t.Fatalf("got %v, but expected the instance of the container", actual)
+		}
+		scope.MustService("repo")
+		cancel()
+
+		deadline := time.Now().Add(time.Second)
+		for scope.State() != izidic.Closed {
+			if time.Now().After(deadline) {
+				t.Fatal("scope not closed after cancellation")
+			}
+			time.Sleep(time.Millisecond)
+		}
+		if _, err := scope.Service("repo"); err == nil {
+			t.Fatal("expected a closed scope not to resolve services")
+		}
+		if err := scope.Close(); !errors.Is(err, errFailed) || strings.Contains(err.Error(), "already closed") {
+			t.Fatalf("got error %v, but expected only the cleanup error", err)
+		}
+		if err := scope.Close(); err == nil || !strings.Contains(err.Error(), "already closed") {
+			t.Fatalf("got error %v, but expected closing twice to fail", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if expected := []string{"repo", "tx"}; !cmp.Equal(closed, expected) {
+			t.Fatalf("unexpected closing: %s", cmp.Diff(closed, expected))
+		}
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		closed = nil
+		scope := dic.WithContext(context.Background())
+		scope.MustService("tx")
+		if err := scope.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := scope.Close(); err == nil {
+			t.Fatal("expected closing twice to fail")
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if expected := []string{"tx"}; !cmp.Equal(closed, expected) {
+			t.Fatalf("unexpected closing: %s", cmp.Diff(closed, expected))
+		}
+	})
+
+	if actual := dic.State(); actual != izidic.Frozen {
+		t.Fatalf("got state %s, but expected the container to remain %s", actual, izidic.Frozen)
+	}
+}
+
+func TestContainer_WithContext_NoLeak(t *testing.T) {
+	dic := izidic.New()
+	dic.Register("s1", s1)
+	dic.Freeze()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	baseline := runtime.NumGoroutine()
+	for i := 0; i < 10; i++ {
+		dic.WithContext(ctx).MustService("s1") // Neither cancelled nor closed.
+	}
+	if actual := runtime.NumGoroutine(); actual > baseline {
+		t.Fatalf("got %d goroutines, but expected no more than %d", actual, baseline)
+	}
+}
+
+func TestContainer_WithContext_StrictUsage(t *testing.T) {
+	dic := izidic.New(izidic.WithStrictUsage("s2"))
+	dic.Register("s1", s1)
+	dic.Register("s2", s2)
+	dic.Freeze()
+	scope := dic.WithContext(context.Background())
+	scope.MustService("s1")
+	if err := scope.Close(); err != nil {
+		t.Fatalf("got error %v, but expected the scope not to check the container requirements", err)
+	}
+	if err := dic.Close(); err == nil || !strings.Contains(err.Error(), "never resolved: s2") {
+		t.Fatalf("got error %v, but expected the container to report s2 as never resolved", err)
+	}
+}
+
+func TestContainer_WithContext_Invalidate(t *testing.T) {
+	mu := sync.Mutex{}
+	var closed []string
+	builds := 0
+	dic := izidic.New()
+	dic.Register("tx", func(izidic.Container) (any, error) {
+		builds++
+		return &closer{name: fmt.Sprint("tx", builds), mu: &mu, log: &closed}, nil
+	})
+	dic.Freeze()
+	scope := dic.WithContext(context.Background())
+	scope.MustService("tx")
+	scope.Invalidate("tx")
+	scope.MustService("tx")
+	if _, err := scope.Rebuild("tx"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := scope.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"tx3", "tx2", "tx1"}; !cmp.Equal(closed, expected) {
+		t.Fatalf("unexpected closing: %s", cmp.Diff(closed, expected))
+	}
+}
+
+func TestContainer_WithContext_AcquireT(t *testing.T) {
+	mu := sync.Mutex{}
+	var closed []string
+	dic := izidic.New()
+	dic.Register("pool", func(izidic.Container) (any, error) { return &closer{name: "pool", mu: &mu, log: &closed}, nil })
+	dic.Register("tx", func(izidic.Container) (any, error) { return &closer{name: "tx", mu: &mu, log: &closed}, nil })
+	dic.Freeze()
+	dic.MustService("pool")
+
+	scope := dic.WithContext(context.Background())
+	for _, name := range []string{"pool", "tx"} {
+		if _, err := izidic.AcquireT[*closer](scope, name); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := scope.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"tx"}; !cmp.Equal(closed, expected) {
+		t.Fatalf("unexpected closing: %s", cmp.Diff(closed, expected))
+	}
+}
@@ -0,0 +1,35 @@
+package izidic
+
+import "runtime"
+
+// maxStackDepth bounds the number of frames captured for creation stacks.
+const maxStackDepth = 64
+
+// CreationStack returns the call stack which led to the first instantiation of
+// a service, starting with the caller of Container.Service, or nil if the
+// service was not instantiated or the container was not created with
+// WithCreationStacks.
+//
+// Frames are in innermost-first order, and include the resolution of the
+// services depending on it, if it was instantiated as a dependency.
+func (dic *container) CreationStack(name string) []runtime.Frame {
+	name = dic.normalize(name)
+	dic.RLock()
+	defer dic.RUnlock()
+	return append([]runtime.Frame(nil), dic.stacks[name]...)
+}
+
+// callers returns the frames of the caller of the function calling it.
+func callers() []runtime.Frame {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(4, pcs) // Skip runtime.Callers, callers, service, and Service.
+	iter := runtime.CallersFrames(pcs[:n])
+	stack := make([]runtime.Frame, 0, n)
+	for {
+		frame, more := iter.Next()
+		stack = append(stack, frame)
+		if !more {
+			return stack
+		}
+	}
+}
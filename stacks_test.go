@@ -0,0 +1,47 @@
+package izidic_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+func TestContainer_CreationStack(t *testing.T) {
+	dic := izidic.New(izidic.WithCreationStacks())
+	dic.Register("s1", s1)
+	dic.Register("s2", s2)
+	dic.Freeze()
+
+	if stack := dic.CreationStack("s1"); stack != nil {
+		t.Fatalf("got stack %v before instantiation, but expected none", stack)
+	}
+	dic.MustService("s2")
+
+	stack := dic.CreationStack("s2")
+	if len(stack) == 0 || !strings.HasSuffix(stack[0].Function, "(*container).MustService") {
+		t.Fatalf("got stack %v, but expected it to start at MustService", stack)
+	}
+	found := false
+	for _, frame := range stack {
+		if strings.HasSuffix(frame.Function, ".TestContainer_CreationStack") {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Fatalf("got stack %v, but expected it to include the test", stack)
+	}
+	// s1 was instantiated as a dependency of s2.
+	if inner := dic.CreationStack("s1"); len(inner) <= len(stack) {
+		t.Fatalf("got %d frames for s1, but expected more than the %d of s2", len(inner), len(stack))
+	}
+
+	dic = izidic.New()
+	dic.Register("s1", s1)
+	dic.Freeze()
+	dic.MustService("s1")
+	if stack := dic.CreationStack("s1"); stack != nil {
+		t.Fatalf("got stack %v without WithCreationStacks, but expected none", stack)
+	}
+}
@@ -0,0 +1,94 @@
+package izidic
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// State is the lifecycle phase of a container.
+type State int
+
+const (
+	// Building is the initial state, in which parameters and services may be
+	// defined, but concurrent access is not supported.
+	Building State = iota
+	// Frozen is the run state, entered by Freeze, in which definitions may no
+	// longer change, but concurrent access is supported.
+	Frozen
+	// Closed is the final state, entered by Close, in which nothing may be resolved.
+	Closed
+)
+
+// String returns the lowercase name of the state.
+func (s State) String() string {
+	switch s {
+	case Building:
+		return "building"
+	case Frozen:
+		return "frozen"
+	case Closed:
+		return "closed"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+// State returns the current lifecycle state of the container.
+func (dic *container) State() State {
+	dic.RLock()
+	defer dic.RUnlock()
+	return dic.state
+}
+
+// Close ends the container lifecycle, from either the Building or Frozen state.
+//
+// Once closed, the container no longer resolves parameters or services.
+//
+// If the container was created WithStrictUsage, Close returns an error listing
+// the required services which were never resolved, after closing anyway.
+//
+// It then closes the instances acquired with AcquireT, joining their errors.
+func (dic *container) Close() error {
+	dic.Lock()
+	if dic.state == Closed {
+		dic.Unlock()
+		return errors.New("container is already closed")
+	}
+	dic.state = Closed
+
+	var unused []string
+	for _, name := range dic.required {
+		if _, found := dic.services[name]; !found {
+			unused = append(unused, name)
+		}
+	}
+	acquired := dic.acquired
+	dic.acquired = nil
+	dic.Unlock()
+
+	var err error
+	if len(unused) > 0 {
+		err = fmt.Errorf("required services never resolved: %s", strings.Join(unused, ", "))
+	}
+	return errors.Join(err, closeAcquired(acquired))
+}
+
+// mustBuild panics unless the container is in the Building state.
+//
+// Callers must hold the lock.
+func (dic *container) mustBuild(action string) {
+	if dic.state != Building {
+		panic(fmt.Sprintf("Cannot %s on %s container", action, dic.state))
+	}
+}
+
+// checkOpen returns an error if the container is in the Closed state.
+//
+// Callers must hold the lock.
+func (dic *container) checkOpen(kind, name string) error {
+	if dic.state == Closed {
+		return fmt.Errorf("cannot resolve %s %q on closed container", kind, name)
+	}
+	return nil
+}
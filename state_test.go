@@ -0,0 +1,72 @@
+package izidic_test
+
+import (
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+func TestContainer_State(t *testing.T) {
+	dic := izidic.New()
+	dic.Store("p", "v")
+	dic.Register("s1", s1)
+	if actual := dic.State(); actual != izidic.Building {
+		t.Fatalf("got state %s, but expected %s", actual, izidic.Building)
+	}
+	dic.Freeze()
+	dic.Freeze() // Idempotent.
+	if actual := dic.State(); actual != izidic.Frozen {
+		t.Fatalf("got state %s, but expected %s", actual, izidic.Frozen)
+	}
+	if err := dic.Close(); err != nil {
+		t.Fatalf("unexpected error closing: %v", err)
+	}
+	if actual := dic.State(); actual != izidic.Closed {
+		t.Fatalf("got state %s, but expected %s", actual, izidic.Closed)
+	}
+	if err := dic.Close(); err == nil {
+		t.Fatal("closing twice did not fail")
+	}
+
+	tests := [...]struct {
+		name     string
+		attempt  func() error
+		expected string
+	}{
+		{"param", func() error { _, err := dic.Param("p"); return err }, `cannot resolve parameter "p" on closed container`},
+		{"service", func() error { _, err := dic.Service("s1"); return err }, `cannot resolve service "s1" on closed container`},
+		{"service with", func() error { _, err := dic.ServiceWith("s1", nil); return err }, `cannot resolve service "s1" on closed container`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.attempt()
+			if err == nil || err.Error() != test.expected {
+				t.Fatalf("got error %v, but expected %q", err, test.expected)
+			}
+		})
+	}
+}
+
+func TestContainer_State_ClosedPanics(t *testing.T) {
+	tests := [...]struct {
+		name     string
+		attempt  func(container izidic.Container)
+		expected string
+	}{
+		{"freeze", func(dic izidic.Container) { dic.Freeze() }, "Cannot freeze closed container"},
+		{"register", func(dic izidic.Container) { dic.Register("s", s1) }, "Cannot register services on closed container"},
+		{"store", func(dic izidic.Container) { dic.Store("p", "v") }, "Cannot store parameters on closed container"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				if rec := recover(); rec != test.expected {
+					t.Fatalf("got %#v, but expected %q", rec, test.expected)
+				}
+			}()
+			dic := izidic.New()
+			_ = dic.Close() // Building -> Closed
+			test.attempt(dic)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package izidic
+
+import "sync/atomic"
+
+// Stats counts the service resolutions on a container.
+type Stats struct {
+	Resolutions    uint64 // Calls to Service, including those from other methods like MustService.
+	Hits           uint64 // Resolutions returning a cached instance.
+	Instantiations uint64 // Successful factory runs.
+	Errors         uint64 // Failed resolutions.
+}
+
+// counters holds the live counts behind Stats.
+type counters struct {
+	resolutions, hits, instantiations, errors atomic.Uint64
+}
+
+// Stats returns the resolution counts since the container was created, or since
+// the last ResetStats, to spot services resolved surprisingly often.
+//
+// Counts are read independently, so they may be slightly inconsistent with each
+// other during concurrent resolutions. A failed resolution of a service counts
+// as an error for it and each of the services depending on it.
+func (dic *container) Stats() Stats {
+	return Stats{
+		Resolutions:    dic.stats.resolutions.Load(),
+		Hits:           dic.stats.hits.Load(),
+		Instantiations: dic.stats.instantiations.Load(),
+		Errors:         dic.stats.errors.Load(),
+	}
+}
+
+// ResetStats resets the resolution counts to zero, for periodic sampling.
+func (dic *container) ResetStats() {
+	dic.stats.resolutions.Store(0)
+	dic.stats.hits.Store(0)
+	dic.stats.instantiations.Store(0)
+	dic.stats.errors.Store(0)
+}
@@ -0,0 +1,31 @@
+package izidic_test
+
+import (
+	"testing"
+
+	"github.com/fgm/izidic"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestContainer_Stats(t *testing.T) {
+	dic := izidic.New()
+	dic.Register("s1", s1)
+	dic.Register("s2", s2)
+	dic.Freeze()
+
+	dic.MustService("s2") // Resolves s1 too.
+	dic.MustService("s1")
+	_, _ = dic.Service("missing")
+
+	expected := izidic.Stats{Resolutions: 4, Hits: 1, Instantiations: 2, Errors: 1}
+	if actual := dic.Stats(); !cmp.Equal(actual, expected) {
+		t.Fatalf("unexpected stats: %s", cmp.Diff(actual, expected))
+	}
+
+	dic.ResetStats()
+	dic.MustService("s2")
+	expected = izidic.Stats{Resolutions: 1, Hits: 1}
+	if actual := dic.Stats(); !cmp.Equal(actual, expected) {
+		t.Fatalf("unexpected stats after reset: %s", cmp.Diff(actual, expected))
+	}
+}
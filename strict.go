@@ -0,0 +1,28 @@
+package izidic
+
+import "fmt"
+
+// strictError reports an undefined dependency requested under WithStrictResolution.
+type strictError struct {
+	requester, name string
+}
+
+func (e *strictError) Error() string {
+	return fmt.Sprintf("strict resolution: service %q requested undefined service %q", e.requester, e.name)
+}
+
+// recoverStrict turns the panic used to abort a strict resolution into an error
+// for the service at the top of the resolution, and lets other panics through.
+//
+// It must be deferred directly.
+func recoverStrict(name string, err *error) {
+	rec := recover()
+	if rec == nil {
+		return
+	}
+	serr, ok := rec.(*strictError)
+	if !ok {
+		panic(rec)
+	}
+	*err = fmt.Errorf("failed instantiating service %s: %w", name, serr)
+}
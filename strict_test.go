@@ -0,0 +1,42 @@
+package izidic_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+func TestWithStrictResolution(t *testing.T) {
+	// lenient builds a service ignoring the failure to resolve its dependency.
+	lenient := func(dep string) izidic.Service {
+		return func(dic izidic.Container) (any, error) {
+			if _, err := dic.Service(dep); err != nil {
+				return "fallback", nil
+			}
+			return dep, nil
+		}
+	}
+	// build builds a container where "mid" handles a missing dependency.
+	build := func(opts ...izidic.Option) izidic.Container {
+		dic := izidic.New(opts...)
+		dic.Register("top", dependent("top", "mid"))
+		dic.Register("mid", lenient("typo"))
+		dic.Freeze()
+		return dic
+	}
+
+	if actual := build().MustService("top"); actual != "top" {
+		t.Fatalf("got %v, but expected the lenient factory to handle the error", actual)
+	}
+
+	dic := build(izidic.WithStrictResolution())
+	const expected = `failed instantiating service top: strict resolution: service "mid" requested undefined service "typo"`
+	if _, err := dic.Service("top"); fmt.Sprint(err) != expected {
+		t.Fatalf("got error %v, but expected %s", err, expected)
+	}
+	// Direct lookups on the container are not affected.
+	if _, err := dic.Service("missing"); fmt.Sprint(err) != `service not found: "missing"` {
+		t.Fatalf("got error %v, but expected a not found error", err)
+	}
+}
@@ -0,0 +1,99 @@
+package izidic
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// Swap replaces all the parameter and service definitions of the container with
+// those registered by build on a fresh shadow container, for blue-green style
+// reconfiguration of a running container without downtime.
+//
+// The shadow container uses the same clock, interceptor, name normalizer,
+// default timeout, logger, and active profile as the container, and is checked like with
+// FreezeOrError before the swap: if build panics or the checks fail, the
+// container is left untouched, and the error is returned.
+//
+// The swap is atomic: it happens under the write lock, together with dropping
+// all cached instances, so resolutions see either the old definitions and
+// instances or the new ones, never a mix. Instances being built from the old
+// definitions during the swap are returned to their callers, but not cached.
+// Dependencies recorded by FreezeWithAnalysis are dropped too.
+//
+// Versioned and type-keyed services are replaced too, and their instances
+// dropped. The instantiation manifest restarts with the new definitions. Old instances are not closed, since callers may still hold them,
+// unless the container was created WithCloseOnSwap.
+func (dic *container) Swap(build func(Container)) (err error) {
+	dic.RLock()
+	shadow := baseOf(New())
+	shadow.clock, shadow.interceptor, shadow.normalizer = dic.clock, dic.interceptor, dic.normalizer
+	shadow.timeout, shadow.logger, shadow.profile = dic.timeout, dic.logger, dic.profile
+	dic.RUnlock()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed building swapped definitions: %v", r)
+		}
+	}()
+	build(shadow)
+	if err := errors.Join(shadow.Validate(), shadow.CheckNamespaceCollisions(), CheckCycles(shadow)); err != nil {
+		return err
+	}
+
+	dic.Lock()
+	if dic.state == Closed {
+		dic.Unlock()
+		return errors.New("cannot swap definitions on closed container")
+	}
+	old, manifest := dic.services, dic.manifest
+	dic.adopt(shadow.definitions())
+	dic.services, dic.built = make(map[string]any), make(map[string]time.Time)
+	dic.manifest, dic.manifested = nil, make(map[string]bool)
+	dic.versionInstances, dic.typedInstances = make(map[string]any), make(map[typeKey]any)
+	dic.edges, dic.analyzed = nil, false
+	dic.flights = nil // Callers must not wait for instances built from the old definitions.
+	dic.generation++
+	closeOld := dic.closeOnSwap
+	dic.Unlock()
+
+	if !closeOld {
+		return nil
+	}
+	return closeInstances(old, manifest)
+}
+
+// closeInstances closes the instances implementing io.Closer, in the reverse
+// order of their first instantiation, then those missing from the manifest in
+// name order.
+func closeInstances(instances map[string]any, manifest []ManifestEntry) error {
+	order := make([]string, 0, len(instances))
+	listed := make(map[string]bool, len(manifest))
+	for i := len(manifest) - 1; i >= 0; i-- {
+		if name := manifest[i].Name; !listed[name] {
+			listed[name] = true
+			order = append(order, name)
+		}
+	}
+	var rest []string
+	for name := range instances {
+		if !listed[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	order = append(order, rest...)
+
+	var errs []error
+	for _, name := range order {
+		closer, ok := instances[name].(io.Closer)
+		if !ok {
+			continue
+		}
+		if err := closer.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("closing service %q: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,117 @@
+package izidic_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fgm/izidic"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestContainer_Swap(t *testing.T) {
+	mu := sync.Mutex{}
+	var closed []string
+	dic := izidic.New(izidic.WithCloseOnSwap())
+	dic.Store("color", "blue")
+	dic.Register("pool", func(izidic.Container) (any, error) {
+		return &closer{name: "pool", mu: &mu, log: &closed}, nil
+	})
+	dic.Register("app", func(dic izidic.Container) (any, error) {
+		dic.MustService("pool")
+		return dic.MustParam("color"), nil
+	})
+	dic.Freeze()
+	if actual := dic.MustService("app"); actual != "blue" {
+		t.Fatalf("got %v, but expected blue", actual)
+	}
+
+	err := dic.Swap(func(dic izidic.Container) {
+		dic.Store("color", "green")
+		dic.Register("app", func(dic izidic.Container) (any, error) {
+			return dic.MustParam("color"), nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual := dic.MustService("app"); actual != "green" {
+		t.Fatalf("got %v, but expected the swapped definition", actual)
+	}
+	if expected := []string{"pool"}; !cmp.Equal(closed, expected) {
+		t.Fatalf("unexpected closing: %s", cmp.Diff(closed, expected))
+	}
+	if expected := []string{"app"}; !cmp.Equal(dic.Names()["services"], expected) {
+		t.Fatalf("unexpected services: %s", cmp.Diff(dic.Names()["services"], expected))
+	}
+
+	checks := [...]struct {
+		name     string
+		build    func(izidic.Container)
+		expected string
+	}{
+		{"panic", func(izidic.Container) { panic("oops") }, "failed building swapped definitions: oops"},
+		{"cycle", func(dic izidic.Container) {
+			dic.Register("a", dependent("a", "b"))
+			dic.Register("b", dependent("b", "a"))
+		}, "circular dependency detected"},
+	}
+	for _, check := range checks {
+		t.Run(check.name, func(t *testing.T) {
+			if err := dic.Swap(check.build); err == nil || !strings.Contains(err.Error(), check.expected) {
+				t.Fatalf("got error %v, but expected it to contain %q", err, check.expected)
+			}
+			if actual := dic.MustService("app"); actual != "green" {
+				t.Fatalf("got %v, but expected the container to be untouched", actual)
+			}
+		})
+	}
+}
+
+func TestContainer_Swap_AllDefinitions(t *testing.T) {
+	dic := izidic.New()
+	dic.RegisterVersioned("api", "1.0.0", func(izidic.Container) (any, error) { return "v1", nil })
+	dic.RegisterVersioned("api", "2.0.0", s1)
+	izidic.ProvideType(dic, func(izidic.Container) (*config, error) { return &config{prefix: "old"}, nil })
+	dic.Freeze()
+	if actual, _ := dic.ServiceVersion("api", "1.0.0"); actual != "v1" {
+		t.Fatalf("got %v, but expected v1", actual)
+	}
+	if cfg, _ := izidic.Resolve[*config](dic); cfg.prefix != "old" {
+		t.Fatalf("got prefix %q, but expected old", cfg.prefix)
+	}
+
+	err := dic.Swap(func(dic izidic.Container) {
+		dic.RegisterVersioned("api", "1.0.0", func(izidic.Container) (any, error) { return "v1 swapped", nil })
+		izidic.ProvideType(dic, func(izidic.Container) (*config, error) { return &config{prefix: "new"}, nil })
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual, err := dic.ServiceVersion("api", "1.0.0"); err != nil || actual != "v1 swapped" {
+		t.Fatalf("got %v, %v, but expected the swapped version", actual, err)
+	}
+	if _, err := dic.ServiceVersion("api", "2.0.0"); err == nil {
+		t.Fatal("version missing from the swapped definitions survived the swap")
+	}
+	if cfg, err := izidic.Resolve[*config](dic); err != nil || cfg.prefix != "new" {
+		t.Fatalf("got %v, %v, but expected the swapped type-keyed service", cfg, err)
+	}
+}
+
+func TestContainer_Swap_Manifest(t *testing.T) {
+	dic := izidic.New()
+	dic.Register("app", s1)
+	dic.Freeze()
+	dic.MustService("app")
+	if err := dic.Swap(func(dic izidic.Container) { dic.Register("app", s1) }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if manifest := dic.InstantiationManifest(); manifest != nil {
+		t.Fatalf("got manifest %v, but expected it to restart", manifest)
+	}
+	dic.MustService("app")
+	if manifest := dic.InstantiationManifest(); len(manifest) != 1 || manifest[0].Name != "app" {
+		t.Fatalf("got manifest %v, but expected the swapped service", manifest)
+	}
+}
@@ -0,0 +1,41 @@
+package izidic
+
+// StoreTagged stores a parameter like Store, and tags it, so that groups of
+// parameters, like feature flags or tunables for an admin UI, can be fetched
+// with ParamsByTag.
+//
+// Tags replace those given by a previous StoreTagged for the same name, while
+// storing the parameter again with Store keeps them.
+func (dic *container) StoreTagged(name string, tags []string, v any) {
+	name = dic.normalize(name)
+	dic.Lock()
+	defer dic.Unlock()
+	dic.mustBuild("store parameters")
+	dic.mustEnum(name, v)
+	dic.ownParams()
+	dic.parameters[name] = v
+	delete(dic.paramAliases, name)
+	delete(dic.paramServices, name)
+	dic.paramTags[name] = append([]string(nil), tags...)
+	dic.names = nil
+}
+
+// ParamsByTag returns the values of the parameters tagged with tag by
+// StoreTagged, keyed by parameter name, or an empty map if none is.
+//
+// As with any map, iteration order is unspecified: sort the keys for a stable
+// display. The map is a new copy, which callers may modify.
+func (dic *container) ParamsByTag(tag string) map[string]any {
+	dic.RLock()
+	defer dic.RUnlock()
+	params := make(map[string]any)
+	for name, tags := range dic.paramTags {
+		for _, t := range tags {
+			if t == tag {
+				params[name] = dic.parameters[name]
+				break
+			}
+		}
+	}
+	return params
+}
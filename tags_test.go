@@ -0,0 +1,39 @@
+package izidic_test
+
+import (
+	"testing"
+
+	"github.com/fgm/izidic"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestContainer_ParamsByTag(t *testing.T) {
+	dic := izidic.New()
+	dic.StoreTagged("beta", []string{"feature-flag"}, true)
+	dic.StoreTagged("dark-mode", []string{"feature-flag", "ui"}, false)
+	dic.StoreTagged("retries", []string{"tunable"}, 3)
+	dic.Store("retries", 5) // Keeps the tags.
+	dic.StoreTagged("beta", []string{"ui"}, true)
+	dic.Store("port", 8080)
+	dic.Freeze()
+
+	checks := [...]struct {
+		tag      string
+		expected map[string]any
+	}{
+		{"feature-flag", map[string]any{"dark-mode": false}},
+		{"ui", map[string]any{"beta": true, "dark-mode": false}},
+		{"tunable", map[string]any{"retries": 5}},
+		{"missing", map[string]any{}},
+	}
+	for _, check := range checks {
+		t.Run(check.tag, func(t *testing.T) {
+			if actual := dic.ParamsByTag(check.tag); !cmp.Equal(actual, check.expected) {
+				t.Fatalf("unexpected params: %s", cmp.Diff(actual, check.expected))
+			}
+		})
+	}
+	if actual := dic.MustParam("beta"); actual != true {
+		t.Fatalf("got %v, but expected the tagged parameter to be stored", actual)
+	}
+}
@@ -0,0 +1,64 @@
+package izidic
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RegisterWithTimeout registers a service whose factory is abandoned if it does
+// not complete within d, so that a stuck factory, like a network dial to an
+// unreachable host, cannot hang startup indefinitely. The resolution error then
+// wraps context.DeadlineExceeded.
+//
+// The factory runs in its own goroutine, and its result is discarded on timeout.
+// Since a Service cannot be cancelled, that goroutine keeps running until the
+// factory returns, and leaks if it never does: prefer factories bounding their
+// own blocking operations, e.g. with a context.WithTimeout, for a clean cancellation.
+//
+// A panic in the factory is propagated to the resolving goroutine. Although the
+// resolution stack is split across goroutines, cycles through such services are
+// still detected, provided their factories resolve their dependencies from the
+// container passed to them, which tracks the services being resolved.
+//
+// A zero or negative d registers the service without any timeout, overriding
+// the default set by WithDefaultTimeout.
+func (dic *container) RegisterWithTimeout(name string, fn Service, d time.Duration) {
+	name = dic.normalize(name)
+	dic.define(name, fn, nil, d)
+}
+
+// withTimeout wraps a service to abandon its factory after d, unless d is not positive.
+func withTimeout(fn Service, d time.Duration) Service {
+	if d <= 0 {
+		return fn
+	}
+	type result struct {
+		instance any
+		err      error
+		panicked any
+	}
+	return func(dic Container) (any, error) {
+		done := make(chan result, 1) // Buffered, so an abandoned factory does not block.
+		go func() {
+			var res result
+			defer func() {
+				if res.panicked = recover(); res.panicked != nil {
+					done <- res
+				}
+			}()
+			res.instance, res.err = fn(dic)
+			done <- res
+		}()
+
+		select {
+		case res := <-done:
+			if res.panicked != nil {
+				panic(res.panicked)
+			}
+			return res.instance, res.err
+		case <-dic.Clock().After(d):
+			return nil, fmt.Errorf("timed out after %s: %w", d, context.DeadlineExceeded)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package izidic_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fgm/izidic"
+)
+
+func TestContainer_RegisterWithTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	dic := izidic.New()
+	dic.RegisterWithTimeout("fast", s1, time.Second)
+	dic.RegisterWithTimeout("stuck", func(izidic.Container) (any, error) {
+		<-release
+		return "late", nil
+	}, 10*time.Millisecond)
+	dic.RegisterWithTimeout("panicking", func(izidic.Container) (any, error) {
+		panic("boom")
+	}, time.Second)
+	dic.Freeze()
+
+	if _, err := dic.Service("fast"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err := dic.Service("stuck")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v, but expected a deadline error", err)
+	}
+	const expected = "failed instantiating service stuck: timed out after 10ms: context deadline exceeded"
+	if err.Error() != expected {
+		t.Fatalf("got error %q, but expected %q", err, expected)
+	}
+
+	defer func() {
+		if rec := recover(); fmt.Sprint(rec) != "boom" {
+			t.Fatalf("got panic %v, but expected boom", rec)
+		}
+	}()
+	_, _ = dic.Service("panicking")
+}
+
+func TestWithDefaultTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	// slow builds a service blocking until the test ends, or d elapses.
+	slow := func(d time.Duration) izidic.Service {
+		return func(izidic.Container) (any, error) {
+			select {
+			case <-release:
+			case <-time.After(d):
+			}
+			return "slow", nil
+		}
+	}
+	dic := izidic.New(izidic.WithDefaultTimeout(10 * time.Millisecond))
+	dic.Register("default", slow(time.Hour))
+	dic.RegisterWithTimeout("longer", slow(30*time.Millisecond), time.Second)
+	dic.RegisterWithTimeout("unbounded", slow(30*time.Millisecond), 0)
+	dic.Freeze()
+
+	tests := [...]struct {
+		name     string
+		expected string
+	}{
+		{"default", "failed instantiating service default: timed out after 10ms: context deadline exceeded"},
+		{"longer", "<nil>"},
+		{"unbounded", "<nil>"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := dic.Service(test.name); fmt.Sprint(err) != test.expected {
+				t.Fatalf("got error %v, but expected %s", err, test.expected)
+			}
+		})
+	}
+}
+
+func TestWithDefaultTimeout_Cycle(t *testing.T) {
+	before := runtime.NumGoroutine()
+	dic := izidic.New(izidic.WithDefaultTimeout(time.Second))
+	dic.Register("a", dependent("a", "b"))
+	dic.Register("b", dependent("b", "a"))
+	dic.Freeze()
+
+	start := time.Now()
+	_, err := dic.Service("a")
+	if !errors.Is(err, izidic.ErrCircularDependency) {
+		t.Fatalf("got error %v, but expected a circular dependency", err)
+	}
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Fatalf("cycle detected after %s, but expected it before the timeout", elapsed)
+	}
+	// The factory goroutines complete once the cycle is reported.
+	for deadline := time.Now().Add(time.Second); runtime.NumGoroutine() > before; {
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d goroutines, but expected %d", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWithDefaultTimeout_Discovery(t *testing.T) {
+	release := make(chan struct{})
+	var abandoned []<-chan error
+	dic := izidic.New(izidic.WithDefaultTimeout(5 * time.Millisecond))
+	for i := 0; i < 8; i++ {
+		dic.Register(fmt.Sprint("dep", i), s1)
+	}
+	for i := 0; i < 4; i++ {
+		errs := make(chan error, 1)
+		abandoned = append(abandoned, errs)
+		dic.Register(fmt.Sprint("slow", i), func(dic izidic.Container) (any, error) {
+			<-release // Only released once the discovery gave up on the factory.
+			var err error
+			for j := 0; j < 8 && err == nil; j++ {
+				_, err = dic.Service(fmt.Sprint("dep", j))
+			}
+			errs <- err
+			return nil, err
+		})
+	}
+
+	err := izidic.CheckCycles(dic)
+	close(release)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, errs := range abandoned {
+		if err := <-errs; err == nil || !strings.Contains(err.Error(), "discovery already finished") {
+			t.Fatalf("got error %v, but expected late resolutions to be refused", err)
+		}
+	}
+}
+
+func TestWithDefaultTimeout_DiscoveryOverlap(t *testing.T) {
+	dic := izidic.New(izidic.WithDefaultTimeout(5 * time.Millisecond))
+	for i := 0; i < 8; i++ {
+		dic.Register(fmt.Sprint("dep", i), s1)
+	}
+	for i := 0; i < 4; i++ {
+		dic.Register(fmt.Sprint("slow", i), func(dic izidic.Container) (any, error) {
+			time.Sleep(8 * time.Millisecond) // Resolves while discovering the next slow factory.
+			for j := 0; j < 8; j++ {
+				dic.Service(fmt.Sprint("dep", j))
+			}
+			return nil, nil
+		})
+	}
+	// Under the race detector, this checks the discovery pass is locked.
+	if err := izidic.CheckCycles(dic); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := dic.Explain("slow0"); err == nil {
+		t.Fatal("expected explaining a timed out service to fail")
+	}
+	time.Sleep(10 * time.Millisecond) // Let the abandoned factories complete.
+}
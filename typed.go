@@ -0,0 +1,189 @@
+package izidic
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// typeKey identifies a type-keyed service: its type, and an optional qualifier
+// distinguishing several providers of the same type.
+type typeKey struct {
+	t         reflect.Type
+	qualifier string
+}
+
+// String formats the key for error messages.
+func (k typeKey) String() string {
+	if k.qualifier == "" {
+		return k.t.String()
+	}
+	return fmt.Sprintf("%s %q", k.t, k.qualifier)
+}
+
+// based is implemented by the containers of this package, allowing package
+// functions to reach the container behind the Container they are given.
+type based interface {
+	base() *container
+}
+
+// base returns the container itself.
+func (dic *container) base() *container {
+	return dic
+}
+
+// baseOf returns the container behind a Container, unwrapping structs embedding
+// a Container field, like application wrappers and Compose facets.
+//
+// It panics for other implementations, like hand-written mocks, since package
+// functions need the container created by New to operate on.
+func baseOf(dic Container) *container {
+	if b, ok := dic.(based); ok {
+		return b.base()
+	}
+	v := reflect.Indirect(reflect.ValueOf(dic))
+	if v.Kind() == reflect.Struct {
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if f.Anonymous && f.IsExported() && f.Type == containerType && !v.Field(i).IsNil() {
+				return baseOf(v.Field(i).Interface().(Container))
+			}
+		}
+	}
+	panic(fmt.Sprintf("%T does not wrap a container created by izidic.New", dic))
+}
+
+// ProvideType registers a provider for the single service of type T, which is
+// then resolved by type with Resolve instead of by name.
+//
+// Type-keyed providers are stored separately from named services: they are not
+// listed by Names, and the same service may be provided under both forms.
+//
+// Providing the same type twice panics, since resolution would be ambiguous:
+// use ProvideTypeNamed to provide several services of the same type.
+func ProvideType[T any](dic Container, fn func(Container) (T, error)) {
+	ProvideTypeNamed(dic, "", fn)
+}
+
+// ProvideTypeNamed registers a provider for a service of type T, distinguished
+// from other services of the same type by a qualifier, to be resolved with
+// ResolveNamed.
+//
+// Providing the same type with the same qualifier twice panics.
+func ProvideTypeNamed[T any](dic Container, qualifier string, fn func(Container) (T, error)) {
+	c := baseOf(dic)
+	k := typeKey{t: reflect.TypeOf((*T)(nil)).Elem(), qualifier: qualifier}
+	c.Lock()
+	defer c.Unlock()
+	c.mustBuild("provide types")
+	if _, found := c.typed[k]; found {
+		panic(fmt.Sprintf("Cannot provide type %s twice", k))
+	}
+	c.typed[k] = func(dic Container) (any, error) { return fn(dic) }
+	c.typedOrder = append(c.typedOrder, k)
+}
+
+// AllImplementing resolves all the type-keyed services whose provided type is
+// assignable to T, typically an interface, like collecting all the http.Handler
+// services, in the order they were provided.
+//
+// Only services provided with ProvideType, ProvideTypeNamed, or Invoke are
+// considered: named services have no declared type, so they are skipped.
+// The first resolution error aborts the collection.
+func AllImplementing[T any](dic Container) ([]T, error) {
+	c := baseOf(dic)
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	c.RLock()
+	var keys []typeKey
+	for _, k := range c.typedOrder {
+		if k.t.AssignableTo(t) {
+			keys = append(keys, k)
+		}
+	}
+	c.RUnlock()
+
+	all := make([]T, 0, len(keys))
+	for _, k := range keys {
+		instance, err := c.resolveType(k)
+		if err != nil {
+			return nil, err
+		}
+		v, _ := instance.(T) // nil for a nil instance.
+		all = append(all, v)
+	}
+	return all, nil
+}
+
+// Resolve returns the single instance of the service of type T registered with
+// ProvideType, building it on first use.
+//
+// If only qualified providers exist for T, the error lists their qualifiers.
+func Resolve[T any](dic Container) (T, error) {
+	return ResolveNamed[T](dic, "")
+}
+
+// ResolveNamed returns the single instance of the service of type T registered
+// with ProvideTypeNamed for the qualifier, building it on first use.
+func ResolveNamed[T any](dic Container, qualifier string) (T, error) {
+	var zero T
+	k := typeKey{t: reflect.TypeOf((*T)(nil)).Elem(), qualifier: qualifier}
+	instance, err := baseOf(dic).resolveType(k)
+	if err != nil {
+		return zero, err
+	}
+	v, _ := instance.(T) // The zero value for a nil instance.
+	return v, nil
+}
+
+// resolveType is the type-keyed counterpart of Service.
+func (dic *container) resolveType(k typeKey) (any, error) {
+	dic.RLock()
+	instance, found := dic.typedInstances[k]
+	fn, defined := dic.typed[k]
+	defs := len(dic.typed)
+	err := dic.checkOpen("service", k.String())
+	if !defined && err == nil {
+		err = dic.noProvider(k)
+	}
+	dic.RUnlock()
+	switch {
+	case err != nil:
+		return nil, err
+	case found:
+		return instance, nil
+	}
+
+	if err := checkCycle("github.com/fgm/izidic.(*container).resolveType", defs); err != nil {
+		return nil, err
+	}
+	if instance, err = fn(dic); err != nil {
+		return nil, fmt.Errorf("failed instantiating service of type %s: %w", k, err)
+	}
+
+	dic.Lock()
+	defer dic.Unlock()
+	if existing, found := dic.typedInstances[k]; found {
+		return existing, nil
+	}
+	dic.typedInstances[k] = instance
+	return instance, nil
+}
+
+// noProvider builds the error for a missing type-keyed provider, listing the
+// qualifiers available for the type, if any.
+//
+// Callers must hold the lock.
+func (dic *container) noProvider(k typeKey) error {
+	var qualifiers []string
+	for other := range dic.typed {
+		if other.t == k.t && other.qualifier != "" {
+			qualifiers = append(qualifiers, other.qualifier)
+		}
+	}
+	if len(qualifiers) == 0 {
+		return fmt.Errorf("no provider for type %s", k)
+	}
+	sort.Strings(qualifiers)
+	return fmt.Errorf("no provider for type %s, available qualifiers: %s", k, strings.Join(qualifiers, ", "))
+}
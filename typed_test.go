@@ -0,0 +1,179 @@
+package izidic_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+type config struct{ prefix string }
+
+func TestResolve(t *testing.T) {
+	dic := izidic.New()
+	dic.Store("prefix", "app: ")
+	builds := 0
+	izidic.ProvideType(dic, func(dic izidic.Container) (*config, error) {
+		builds++
+		return &config{prefix: dic.MustParam("prefix").(string)}, nil
+	})
+	izidic.ProvideType(dic, func(dic izidic.Container) (*log.Logger, error) {
+		cfg, err := izidic.Resolve[*config](dic)
+		if err != nil {
+			return nil, err
+		}
+		return log.New(&bytes.Buffer{}, cfg.prefix, 0), nil
+	})
+	dic.Freeze()
+
+	logger, err := izidic.Resolve[*log.Logger](dic)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual := logger.Prefix(); actual != "app: " {
+		t.Fatalf("got prefix %q, but expected %q", actual, "app: ")
+	}
+	again, _ := izidic.Resolve[*log.Logger](dic)
+	cfg, _ := izidic.Resolve[*config](dic)
+	if again != logger || builds != 1 || cfg.prefix != "app: " {
+		t.Fatalf("typed services are not singletons")
+	}
+	if names := dic.Names()["services"]; len(names) != 0 {
+		t.Fatalf("got named services %v, but expected none", names)
+	}
+
+	_, err = izidic.Resolve[fmt.Stringer](dic)
+	if expected := "no provider for type fmt.Stringer"; fmt.Sprint(err) != expected {
+		t.Fatalf("got error %v, but expected %q", err, expected)
+	}
+}
+
+func TestResolve_Cycle(t *testing.T) {
+	type a struct{}
+	type b struct{}
+	dic := izidic.New()
+	izidic.ProvideType(dic, func(dic izidic.Container) (a, error) {
+		_, err := izidic.Resolve[b](dic)
+		return a{}, err
+	})
+	izidic.ProvideType(dic, func(dic izidic.Container) (b, error) {
+		_, err := izidic.Resolve[a](dic)
+		return b{}, err
+	})
+	_, err := izidic.Resolve[a](dic)
+	if err == nil || !strings.HasSuffix(err.Error(), "circular dependency detected") {
+		t.Fatalf("got unexpected error: %v", err)
+	}
+}
+
+func TestResolveNamed(t *testing.T) {
+	dic := izidic.New()
+	for _, q := range []string{"primary", "replica"} {
+		q := q
+		izidic.ProvideTypeNamed(dic, q, func(izidic.Container) (*config, error) {
+			return &config{prefix: q}, nil
+		})
+	}
+	dic.Freeze()
+
+	for _, q := range []string{"primary", "replica"} {
+		cfg, err := izidic.ResolveNamed[*config](dic, q)
+		if err != nil || cfg.prefix != q {
+			t.Fatalf("got %v, %v, but expected config %q", cfg, err, q)
+		}
+	}
+
+	_, err := izidic.Resolve[*config](dic)
+	const expected = "no provider for type *izidic_test.config, available qualifiers: primary, replica"
+	if fmt.Sprint(err) != expected {
+		t.Fatalf("got error %v, but expected %q", err, expected)
+	}
+}
+
+func TestResolveNamed_NilInterface(t *testing.T) {
+	dic := izidic.New()
+	izidic.ProvideTypeNamed(dic, "discard", func(izidic.Container) (io.Writer, error) {
+		return nil, nil
+	})
+	dic.Freeze()
+	w, err := izidic.ResolveNamed[io.Writer](dic, "discard")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if w != nil {
+		t.Fatalf("got %v, but expected a nil writer", w)
+	}
+}
+
+// wrapper is an application container, embedding Container to add accessors.
+type wrapper struct{ izidic.Container }
+
+func TestResolve_Wrapper(t *testing.T) {
+	app := &wrapper{Container: izidic.New()}
+	izidic.ProvideType(app, func(izidic.Container) (*config, error) {
+		return &config{prefix: "wrapped"}, nil
+	})
+	app.Freeze()
+	cfg, err := izidic.Resolve[*config](app)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.prefix != "wrapped" {
+		t.Fatalf("got prefix %q, but expected %q", cfg.prefix, "wrapped")
+	}
+
+	defer func() {
+		if rec := recover(); rec == nil {
+			t.Fatal("resolving from a wrapper without container did not panic")
+		}
+	}()
+	_, _ = izidic.Resolve[*config](wrapper{})
+}
+
+func TestProvideType_Duplicate(t *testing.T) {
+	defer func() {
+		const expected = "Cannot provide type *izidic_test.config twice"
+		if rec := recover(); rec != expected {
+			t.Fatalf("got %#v, but expected %q", rec, expected)
+		}
+	}()
+	provider := func(izidic.Container) (*config, error) { return &config{}, nil }
+	dic := izidic.New()
+	izidic.ProvideType(dic, provider)
+	izidic.ProvideType(dic, provider)
+}
+
+type greeter interface{ greet() string }
+
+type english struct{}
+
+func (english) greet() string { return "hello" }
+
+type french struct{}
+
+func (*french) greet() string { return "bonjour" }
+
+func TestAllImplementing(t *testing.T) {
+	dic := izidic.New()
+	izidic.ProvideType(dic, func(izidic.Container) (*french, error) { return &french{}, nil })
+	izidic.ProvideType(dic, func(izidic.Container) (*config, error) { return &config{}, nil })
+	izidic.ProvideTypeNamed(dic, "en", func(izidic.Container) (english, error) { return english{}, nil })
+	dic.Register("named", func(izidic.Container) (any, error) { return english{}, nil }) // Skipped.
+	dic.Freeze()
+
+	all, err := izidic.AllImplementing[greeter](dic)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actual := make([]string, len(all))
+	for i, g := range all {
+		actual[i] = g.greet()
+	}
+	if expected := "bonjour hello"; strings.Join(actual, " ") != expected {
+		t.Fatalf("got %q, but expected %q in provision order", actual, expected)
+	}
+}
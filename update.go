@@ -0,0 +1,78 @@
+package izidic
+
+import "fmt"
+
+// Update replaces the value of a parameter, including on a frozen container,
+// as the primitive for runtime configuration changes, like a hot reload, unlike
+// Store which is limited to build mode.
+//
+// Only parameters declared with DeclareParam may be updated, to prevent adding
+// new names at runtime, and the new value must satisfy their ParamSpec.
+// It returns an error otherwise, or if the container is closed.
+//
+// Concurrency: Update takes the write lock, so it is safe with concurrent calls
+// to Param, which return either the previous or the new value. Services do not
+// observe updates: those built before keep the values their factories read,
+// until rebuilt, as with Rebuild or Invalidate, or automatically for services
+// declared with DependsOnParam.
+func (dic *container) Update(name string, v any) error {
+	name = dic.normalize(name)
+	dic.RLock()
+	spec, declared := dic.specs[name]
+	_, enum := dic.enums[name]
+	enumErr := dic.checkEnumParam(name, v)
+	state := dic.state
+	dic.RUnlock()
+	switch {
+	case state == Closed:
+		return fmt.Errorf("cannot update parameter %q on closed container", name)
+	case !declared && !enum:
+		return fmt.Errorf("parameter %q: not declared, cannot update", name)
+	case enumErr != nil:
+		return fmt.Errorf("parameter %q: %w", name, enumErr)
+	}
+	// Validators are user code, so they must not run while holding the lock.
+	if err := spec.check(v); err != nil {
+		return fmt.Errorf("parameter %q: %w", name, err)
+	}
+
+	dic.Lock()
+	if dic.state == Closed {
+		dic.Unlock()
+		return fmt.Errorf("cannot update parameter %q on closed container", name)
+	}
+	if _, stored := dic.parameters[name]; !stored {
+		dic.names = nil
+	}
+	dic.ownParams()
+	dic.parameters[name] = v
+	delete(dic.paramAliases, name)
+	delete(dic.paramServices, name)
+	watchers := dic.watchers[name]
+	dic.Unlock()
+
+	for _, service := range watchers {
+		dic.Invalidate(service)
+	}
+	return nil
+}
+
+// DependsOnParam declares that a service is built from parameters, so that
+// updating any of them with Update invalidates its cached instance, which is
+// then rebuilt on its next resolution, like a client rebuilt after its
+// configuration changed.
+//
+// Invalidation follows Invalidate: the dependents of the service are only
+// invalidated too if dependencies were recorded by FreezeWithAnalysis. Without
+// them, dependents keep the instance they were built with.
+func (dic *container) DependsOnParam(serviceName string, paramNames ...string) {
+	serviceName = dic.normalize(serviceName)
+	dic.Lock()
+	defer dic.Unlock()
+	dic.mustBuild("declare parameter dependencies")
+	for _, name := range paramNames {
+		name = dic.normalize(name)
+		watchers := dic.watchers[name]
+		dic.watchers[name] = append(watchers[:len(watchers):len(watchers)], serviceName) // Clones may share the slice.
+	}
+}
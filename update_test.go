@@ -0,0 +1,95 @@
+package izidic_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+func TestContainer_Update(t *testing.T) {
+	dic := izidic.New()
+	dic.DeclareParam("level", izidic.ParamSpec{Kind: reflect.String, Validator: func(v any) error {
+		if v == "" {
+			return errors.New("empty level")
+		}
+		return nil
+	}})
+	dic.Store("level", "info")
+	dic.Store("name", "demo")
+	dic.Register("logger", func(dic izidic.Container) (any, error) {
+		return dic.MustParam("level"), nil
+	})
+	dic.Freeze()
+	dic.MustService("logger")
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dic.MustParam("level")
+		}()
+	}
+	if err := dic.Update("level", "debug"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wg.Wait()
+	if actual := dic.MustParam("level"); actual != "debug" {
+		t.Fatalf("got %v, but expected the updated value", actual)
+	}
+	if actual := dic.MustService("logger"); actual != "info" {
+		t.Fatalf("got %v, but expected the service built before to keep its value", actual)
+	}
+
+	checks := [...]struct {
+		name, param string
+		value       any
+		expected    string
+	}{
+		{"undeclared", "name", "other", `parameter "name": not declared, cannot update`},
+		{"kind", "level", 42, `parameter "level": expected string, got int`},
+		{"validator", "level", "", `parameter "level": empty level`},
+	}
+	for _, check := range checks {
+		t.Run(check.name, func(t *testing.T) {
+			if err := dic.Update(check.param, check.value); err == nil || err.Error() != check.expected {
+				t.Fatalf("got error %v, but expected %q", err, check.expected)
+			}
+		})
+	}
+
+	_ = dic.Close()
+	if err := dic.Update("level", "warn"); err == nil || !strings.Contains(err.Error(), "closed container") {
+		t.Fatalf("got error %v, but expected a closed container error", err)
+	}
+}
+
+func TestContainer_DependsOnParam(t *testing.T) {
+	dic := izidic.New()
+	dic.DeclareParam("level", izidic.ParamSpec{Kind: reflect.String})
+	dic.Store("level", "info")
+	dic.Register("logger", func(dic izidic.Container) (any, error) {
+		return "logger at " + dic.MustParam("level").(string), nil
+	})
+	dic.Register("app", func(dic izidic.Container) (any, error) {
+		return dic.MustService("logger"), nil
+	})
+	dic.DependsOnParam("logger", "level")
+	dic.Freeze()
+	dic.MustService("app")
+
+	if err := dic.Update("level", "debug"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual := dic.MustService("logger"); actual != "logger at debug" {
+		t.Fatalf("got %v, but expected the service to be rebuilt", actual)
+	}
+	// Without analysis, dependents are not invalidated.
+	if actual := dic.MustService("app"); actual != "logger at info" {
+		t.Fatalf("got %v, but expected the dependent to keep its instance", actual)
+	}
+}
@@ -0,0 +1,105 @@
+package izidic
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ParamSpec describes the expectations on a parameter, checked by Validate.
+type ParamSpec struct {
+	// Required parameters must be stored before validation.
+	Required bool
+	// Kind is the expected kind of the parameter value. reflect.Invalid accepts any kind.
+	Kind reflect.Kind
+	// Validator, if not nil, checks the parameter value once its kind is verified.
+	Validator func(value any) error
+}
+
+// DeclareParam declares the expectations on a parameter, to be checked by Validate
+// and Freeze.
+//
+// Parameters may be declared before or after being stored.
+func (dic *container) DeclareParam(name string, spec ParamSpec) {
+	name = dic.normalize(name)
+	dic.Lock()
+	defer dic.Unlock()
+	dic.mustBuild("declare parameters")
+	dic.specs[name] = spec
+}
+
+// Validate checks all declared parameters, and those constrained by StoreEnum,
+// against their stored values.
+//
+// Parameter aliases are checked against the value of their target. Parameters
+// stored by StoreFromService satisfy Required, but are not checked otherwise,
+// since that would resolve their service.
+//
+// It returns nil if all are valid, or an error joining one error per violation,
+// in parameter name order.
+func (dic *container) Validate() error {
+	type check struct {
+		name    string
+		spec    ParamSpec
+		value   any
+		stored  bool
+		derived bool
+		enumErr error
+	}
+	dic.RLock()
+	names := make([]string, 0, len(dic.specs))
+	for name := range dic.specs {
+		names = append(names, name)
+	}
+	for name := range dic.enums {
+		if _, declared := dic.specs[name]; !declared {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	checks := make([]check, len(names))
+	for i, name := range names {
+		target := dic.paramTarget(name)
+		value, stored := dic.parameters[target]
+		_, derived := dic.paramServices[target]
+		checks[i] = check{name: name, spec: dic.specs[name], value: value, stored: stored, derived: derived}
+		if stored {
+			checks[i].enumErr = dic.checkEnumParam(target, value)
+		}
+	}
+	dic.RUnlock()
+
+	// Validators are user code, so they must not run while holding the lock.
+	var errs []error
+	for _, c := range checks {
+		switch {
+		case c.derived:
+			continue
+		case !c.stored:
+			if c.spec.Required {
+				errs = append(errs, fmt.Errorf("parameter %q: required but not stored", c.name))
+			}
+			continue
+		}
+		if err := c.spec.check(c.value); err != nil {
+			errs = append(errs, fmt.Errorf("parameter %q: %w", c.name, err))
+			continue
+		}
+		if c.enumErr != nil {
+			errs = append(errs, fmt.Errorf("parameter %q: %w", c.name, c.enumErr))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// check verifies a stored value against the spec.
+func (spec ParamSpec) check(value any) error {
+	if spec.Kind != reflect.Invalid && reflect.ValueOf(value).Kind() != spec.Kind {
+		return fmt.Errorf("expected %s, got %T", spec.Kind, value)
+	}
+	if spec.Validator == nil {
+		return nil
+	}
+	return spec.Validator(value)
+}
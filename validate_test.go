@@ -0,0 +1,105 @@
+package izidic_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fgm/izidic"
+)
+
+func TestContainer_Validate(t *testing.T) {
+	positive := func(v any) error {
+		if v.(int) <= 0 {
+			return errors.New("must be positive")
+		}
+		return nil
+	}
+	dic := izidic.New()
+	dic.DeclareParam("host", izidic.ParamSpec{Required: true, Kind: reflect.String})
+	dic.DeclareParam("optional", izidic.ParamSpec{Kind: reflect.Bool})
+	dic.DeclareParam("port", izidic.ParamSpec{Required: true, Kind: reflect.Int, Validator: positive})
+	dic.DeclareParam("workers", izidic.ParamSpec{Kind: reflect.Int, Validator: positive})
+	dic.Store("port", "8080")
+	dic.Store("workers", 0)
+
+	const expected = `parameter "host": required but not stored
+parameter "port": expected int, got string
+parameter "workers": must be positive`
+	err := dic.Validate()
+	if err == nil || err.Error() != expected {
+		t.Fatalf("got error:\n%v\nbut expected:\n%s", err, expected)
+	}
+
+	func() {
+		defer func() {
+			rec, _ := recover().(error)
+			if rec == nil || rec.Error() != expected {
+				t.Fatalf("got %#v, but expected a panic with the validation error", rec)
+			}
+		}()
+		dic.Freeze()
+	}()
+	if actual := dic.State(); actual != izidic.Building {
+		t.Fatalf("got state %s after failed freeze, but expected %s", actual, izidic.Building)
+	}
+
+	dic.Store("host", "localhost")
+	dic.Store("port", 8080)
+	dic.Store("workers", 4)
+	if err := dic.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dic.Freeze()
+}
+
+func TestContainer_Validate_AliasesAndDerived(t *testing.T) {
+	dic := izidic.New()
+	dic.Store("port", 8080)
+	if err := dic.AliasParam("listen", "port"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dic.Register("config", s1)
+	dic.StoreFromService("settings", "config")
+	dic.DeclareParam("listen", izidic.ParamSpec{Required: true, Kind: reflect.Int})
+	dic.DeclareParam("settings", izidic.ParamSpec{Required: true, Kind: reflect.Int})
+	if err := dic.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dic.DeclareParam("listen", izidic.ParamSpec{Kind: reflect.String})
+	const expected = `parameter "listen": expected string, got int`
+	if err := dic.Validate(); err == nil || err.Error() != expected {
+		t.Fatalf("got error %v, but expected %s", err, expected)
+	}
+}
+
+func TestContainer_Validate_ValidatorReadsContainer(t *testing.T) {
+	dic := izidic.New()
+	dic.Store("min", 1)
+	dic.Store("workers", 4)
+	dic.DeclareParam("min", izidic.ParamSpec{Kind: reflect.Int})
+	validating := make(chan struct{})
+	dic.DeclareParam("workers", izidic.ParamSpec{Validator: func(v any) error {
+		close(validating)
+		time.Sleep(10 * time.Millisecond) // Let the writer queue for the lock.
+		if v.(int) < dic.MustParam("min").(int) {
+			return errors.New("below min")
+		}
+		return nil
+	}})
+
+	done := make(chan error)
+	go func() { done <- dic.Validate() }()
+	<-validating
+	go dic.Update("min", 2)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("validation deadlocked")
+	}
+}
@@ -0,0 +1,268 @@
+package izidic
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// semver is a parsed semantic version: MAJOR.MINOR.PATCH[-PRERELEASE][+BUILD],
+// optionally prefixed by "v". Build metadata is ignored.
+type semver struct {
+	major, minor, patch int
+	pre                 []string
+}
+
+func parseSemver(s string) (semver, error) {
+	var v semver
+	core := strings.TrimPrefix(s, "v")
+	if i := strings.IndexByte(core, '+'); i >= 0 {
+		core = core[:i]
+	}
+	if i := strings.IndexByte(core, '-'); i >= 0 {
+		v.pre = strings.Split(core[i+1:], ".")
+		core = core[:i]
+		for _, id := range v.pre {
+			if id == "" {
+				return v, fmt.Errorf("invalid version %q: empty prerelease identifier", s)
+			}
+			if _, err := strconv.Atoi(id); err == nil && leadingZero(id) {
+				return v, fmt.Errorf("invalid version %q: numeric identifier %q has a leading zero", s, id)
+			}
+		}
+	}
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return v, fmt.Errorf("invalid version %q: expected MAJOR.MINOR.PATCH", s)
+	}
+	nums := [3]*int{&v.major, &v.minor, &v.patch}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || leadingZero(part) {
+			return v, fmt.Errorf("invalid version %q: %q is not a version number", s, part)
+		}
+		*nums[i] = n
+	}
+	return v, nil
+}
+
+// leadingZero tells whether a numeric version part has a leading zero, which
+// semver forbids, so that "1.02.0" is not silently equal to "1.2.0".
+func leadingZero(part string) bool {
+	return len(part) > 1 && part[0] == '0'
+}
+
+// String returns the canonical form of the version, without a "v" prefix.
+func (v semver) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+	if len(v.pre) > 0 {
+		s += "-" + strings.Join(v.pre, ".")
+	}
+	return s
+}
+
+// compare returns -1, 0, or 1 depending on v being lower, equal, or greater than w,
+// following semver precedence rules.
+func (v semver) compare(w semver) int {
+	for _, pair := range [3][2]int{{v.major, w.major}, {v.minor, w.minor}, {v.patch, w.patch}} {
+		if c := compareInts(pair[0], pair[1]); c != 0 {
+			return c
+		}
+	}
+	// A version without prerelease has precedence over one with a prerelease.
+	switch {
+	case len(v.pre) == 0 && len(w.pre) == 0:
+		return 0
+	case len(v.pre) == 0:
+		return 1
+	case len(w.pre) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.pre) && i < len(w.pre); i++ {
+		vn, verr := strconv.Atoi(v.pre[i])
+		wn, werr := strconv.Atoi(w.pre[i])
+		var c int
+		switch {
+		case verr == nil && werr == nil:
+			c = compareInts(vn, wn)
+		case verr == nil: // Numeric identifiers have lower precedence.
+			c = -1
+		case werr == nil:
+			c = 1
+		default:
+			c = strings.Compare(v.pre[i], w.pre[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(v.pre), len(w.pre))
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+type versionedService struct {
+	version semver
+	fn      Service
+}
+
+// RegisterVersioned registers a version of a service. It panics if the version
+// is not a valid semantic version, like "1.2.3" or "v2.0.0-rc.1".
+//
+// Service(name) resolves the latest version, by semver precedence, among those
+// registered, while ServiceVersion selects a specific version.
+// A plain Register for the same name replaces all its versions, and a definition
+// for the active profile, registered by RegisterProfile, discards them.
+func (dic *container) RegisterVersioned(name, version string, fn Service) {
+	name = dic.normalize(name)
+	mustFactory(name, fn)
+	v, err := parseSemver(version)
+	if err != nil {
+		panic(fmt.Sprintf("Cannot register service %q: %v", name, err))
+	}
+	dic.RLock()
+	interceptor, timeout := dic.interceptor, dic.timeout
+	dic.RUnlock()
+	if interceptor != nil {
+		fn = interceptor(name, fn)
+	}
+	fn = withTimeout(fn, timeout)
+
+	dic.Lock()
+	defer dic.Unlock()
+	dic.mustBuild("register services")
+	if dic.overrides[name] {
+		return // A definition for the active profile replaces all versions.
+	}
+	if dic.versions[name] == nil {
+		dic.versions[name] = make(map[string]versionedService)
+	}
+	dic.versions[name][v.String()] = versionedService{version: v, fn: dic.own(name, fn)}
+	dic.ownDefs()
+	dic.serviceDefs[name] = dic.latestVersion(name).fn
+	dic.names = nil
+	delete(dic.meta, name)
+	delete(dic.ttls, name)
+	delete(dic.nilable, name)
+}
+
+// latestVersion returns the versioned service with the highest precedence.
+//
+// Callers must hold the lock.
+func (dic *container) latestVersion(name string) versionedService {
+	var latest versionedService
+	first := true
+	for _, vs := range dic.versions[name] {
+		if first || vs.version.compare(latest.version) > 0 {
+			latest, first = vs, false
+		}
+	}
+	return latest
+}
+
+// ServiceVersion returns the single instance of a specific version of a service
+// registered with RegisterVersioned.
+//
+// The latest version shares its instance with Service(name). Other versions are
+// instantiated like services, with cycle detection, resolution hooks, and stats,
+// and listed in the instantiation manifest as "name@version".
+func (dic *container) ServiceVersion(name, version string) (any, error) {
+	return dic.serviceVersion(dic.normalize(name), version, nil)
+}
+
+// serviceVersion resolves a version of a service, requested by the factory of
+// the last service in chain, if any.
+func (dic *container) serviceVersion(name, version string, chain []string) (instance any, err error) {
+	v, err := parseSemver(version)
+	if err != nil {
+		return nil, err
+	}
+	key := name + "@" + v.String()
+
+	dic.RLock()
+	err = dic.checkOpen("service", key)
+	vs, found := dic.versions[name][v.String()]
+	isLatest := found && dic.latestVersion(name).version.compare(v) == 0
+	instance, cached := dic.versionInstances[key]
+	generation := dic.generation
+	dic.RUnlock()
+	if err == nil && isLatest {
+		return dic.service(name, chain)
+	}
+
+	dic.stats.resolutions.Add(1)
+	defer func() {
+		if err != nil {
+			dic.stats.errors.Add(1)
+		}
+	}()
+	if dic.strict {
+		defer recoverStrict(key, &err)
+	}
+	switch {
+	case err != nil:
+		return nil, err
+	case !found:
+		return nil, fmt.Errorf("service not found: %q version %s", name, v)
+	case cached:
+		dic.stats.hits.Add(1)
+		return instance, nil
+	}
+
+	f, lead, err := dic.join(key, chain, func() (any, bool) {
+		instance, cached := dic.versionInstances[key]
+		return instance, cached
+	})
+	switch {
+	case err != nil:
+		return nil, err
+	case !lead:
+		dic.stats.hits.Add(1)
+		return dic.wait(f, chain)
+	}
+	defer dic.land(key, f)
+	f.instance, f.err = dic.instantiateVersion(name, key, vs.fn, chain, generation)
+	return f.instance, f.err
+}
+
+// instantiateVersion builds a version of a service for the flight led by
+// serviceVersion, and caches it unless Swap replaced the definitions since
+// generation.
+func (dic *container) instantiateVersion(name, key string, fn Service, chain []string, generation uint64) (any, error) {
+	instance, view, elapsed, err := dic.build(name, key, fn, chain)
+	if err != nil {
+		return nil, err
+	}
+	dic.Lock()
+	defer dic.Unlock()
+	if dic.generation != generation {
+		return instance, nil // Built from definitions replaced by Swap: do not cache it.
+	}
+	if existing, found := dic.versionInstances[key]; found {
+		return existing, nil
+	}
+	dic.versionInstances[key] = instance
+	dic.record(key, elapsed, view)
+	return instance, nil
+}
+
+// ServiceVersion reports a factory requesting a version being resolved as a cycle.
+func (v *factoryView) ServiceVersion(name, version string) (any, error) {
+	dic := v.base()
+	name = dic.normalize(name)
+	if sv, err := parseSemver(version); err == nil {
+		if err := v.checkChain(name + "@" + sv.String()); err != nil {
+			return nil, err
+		}
+	}
+	return dic.serviceVersion(name, version, v.chain)
+}
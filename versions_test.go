@@ -0,0 +1,107 @@
+package izidic_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/fgm/izidic"
+)
+
+func TestContainer_RegisterVersioned(t *testing.T) {
+	versioned := func(v string) izidic.Service {
+		return func(izidic.Container) (any, error) { return "logger " + v, nil }
+	}
+	dic := izidic.New()
+	for _, v := range []string{"1.2.0", "1.10.0", "2.0.0-rc.1", "2.0.0-beta.2", "2.0.0-beta.11", "1.10.0-beta"} {
+		dic.RegisterVersioned("logger", v, versioned(v))
+	}
+	dic.Freeze()
+
+	if actual := dic.MustService("logger"); actual != "logger 2.0.0-rc.1" {
+		t.Fatalf("got %#v, but expected the latest version", actual)
+	}
+	tests := [...]struct {
+		version  string
+		expected string
+	}{
+		{"1.2.0", "logger 1.2.0"},
+		{"v1.10.0", "logger 1.10.0"},
+		{"2.0.0-beta.11", "logger 2.0.0-beta.11"},
+		{"2.0.0-rc.1+build.5", "logger 2.0.0-rc.1"},
+	}
+	for _, test := range tests {
+		t.Run(test.version, func(t *testing.T) {
+			actual, err := dic.ServiceVersion("logger", test.version)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual != test.expected {
+				t.Fatalf("got %#v, but expected %q", actual, test.expected)
+			}
+		})
+	}
+
+	_, err := dic.ServiceVersion("logger", "3.0.0")
+	if expected := `service not found: "logger" version 3.0.0`; fmt.Sprint(err) != expected {
+		t.Fatalf("got error %v, but expected %q", err, expected)
+	}
+	if _, err := dic.ServiceVersion("logger", "3.0"); err == nil {
+		t.Fatal("invalid version did not fail")
+	}
+}
+
+func TestContainer_RegisterVersioned_Invalid(t *testing.T) {
+	defer func() {
+		const expected = `Cannot register service "s": invalid version "1.x.0": "x" is not a version number`
+		if rec := recover(); rec != expected {
+			t.Fatalf("got %#v, but expected %q", rec, expected)
+		}
+	}()
+	izidic.New().RegisterVersioned("s", "1.x.0", s1)
+}
+
+func TestContainer_ServiceVersion_instantiation(t *testing.T) {
+	dic := izidic.New()
+	dic.RegisterVersioned("s", "1.0.0", func(dic izidic.Container) (any, error) {
+		return dic.ServiceVersion("s", "1.0.0")
+	})
+	dic.RegisterVersioned("s", "1.1.0", func(izidic.Container) (any, error) { return "old", nil })
+	dic.RegisterVersioned("s", "2.0.0", func(izidic.Container) (any, error) { return "new", nil })
+	var hooked []any
+	dic.AfterResolve("s", func(instance any, _ error) { hooked = append(hooked, instance) })
+	dic.Freeze()
+
+	if _, err := dic.ServiceVersion("s", "1.0.0"); !errors.Is(err, izidic.ErrCircularDependency) {
+		t.Fatalf("got error %v, but expected a circular dependency", err)
+	}
+	for range [2]struct{}{} {
+		if actual, err := dic.ServiceVersion("s", "1.1.0"); err != nil || actual != "old" {
+			t.Fatalf("got %#v, %v, but expected the old version", actual, err)
+		}
+	}
+	if expected := []any{nil, "old"}; fmt.Sprint(hooked) != fmt.Sprint(expected) {
+		t.Fatalf("got hooks for %v, but expected %v", hooked, expected)
+	}
+	manifest := dic.InstantiationManifest()
+	if len(manifest) != 1 || manifest[0].Name != "s@1.1.0" {
+		t.Fatalf("got manifest %v, but expected the old version", manifest)
+	}
+	if stats := dic.Stats(); stats.Instantiations != 1 || stats.Hits != 1 {
+		t.Fatalf("got stats %+v, but expected 1 instantiation and 1 hit", stats)
+	}
+}
+
+func TestContainer_ServiceVersion_leadingZero(t *testing.T) {
+	dic := izidic.New()
+	dic.RegisterVersioned("s", "1.2.0", s1)
+	dic.Freeze()
+	for _, version := range []string{"01.2.0", "1.02.0", "1.2.00", "1.2.0-rc.01"} {
+		if _, err := dic.ServiceVersion("s", version); err == nil {
+			t.Errorf("version %s did not fail", version)
+		}
+	}
+	if _, err := dic.ServiceVersion("s", "1.2.0"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
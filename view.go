@@ -0,0 +1,169 @@
+package izidic
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// selfError reports a factory requesting its own service.
+type selfError struct {
+	name string
+}
+
+func (e *selfError) Error() string {
+	return fmt.Sprintf("service %q requested itself during instantiation", e.name)
+}
+
+// Unwrap makes self-resolutions match ErrCircularDependency, as single-node cycles.
+func (e *selfError) Unwrap() error {
+	return ErrCircularDependency
+}
+
+// factoryContainer returns the Container to pass to the factory of a service
+// requested by the factory of the last service in chain, if any.
+func (dic *container) factoryContainer(name string, chain []string) *factoryView {
+	return &factoryView{
+		Container: dic,
+		requester: name,
+		chain:     append(chain[:len(chain):len(chain)], name),
+		strict:    dic.strict,
+	}
+}
+
+// factoryView is the Container passed to factories, knowing which service they
+// build, so that resolutions from it can report self-resolution precisely, and
+// which services are being resolved, so that it detects cycles even when
+// factories run on other goroutines, as with RegisterWithTimeout, where the
+// call stack does not show them.
+//
+// Under WithStrictResolution, it also aborts the resolution with a panic when a
+// factory requests an undefined service, or when a nested resolution was
+// aborted, so that factories cannot handle these errors, and the top-level
+// Service recovers it.
+type factoryView struct {
+	Container
+	requester string
+	chain     []string // The services being resolved, outermost first, ending with requester.
+	strict    bool
+	nested    atomic.Int64 // Nanoseconds spent resolving services from the view.
+
+	mu    sync.Mutex
+	built []string // Services first instantiated by resolutions from the view.
+}
+
+func (v *factoryView) base() *container {
+	return baseOf(v.Container)
+}
+
+func (v *factoryView) MustService(name string) any {
+	instance, err := v.Service(name)
+	if err != nil {
+		panic(err)
+	}
+	return instance
+}
+
+func (v *factoryView) MustServiceN(name Name) any {
+	return v.MustService(string(name))
+}
+
+func (v *factoryView) Service(name string) (any, error) {
+	name = v.base().normalize(name)
+	if err := v.checkChain(name); err != nil {
+		return nil, err
+	}
+	dic := v.base()
+	switch {
+	case !dic.opaque:
+		dic.RLock()
+		manifested := dic.manifested[name]
+		dic.RUnlock()
+		start := time.Now()
+		defer func() {
+			v.nested.Add(int64(time.Since(start)))
+			if !manifested {
+				v.track(name)
+			}
+		}()
+	case dic.budget > 0:
+		start := time.Now()
+		defer func() { v.nested.Add(int64(time.Since(start))) }()
+	}
+	if !v.strict {
+		return dic.service(name, v.chain)
+	}
+	dic.RLock()
+	_, defined := dic.serviceDefs[name]
+	dic.RUnlock()
+	if !defined {
+		panic(&strictError{requester: v.requester, name: name})
+	}
+	instance, err := dic.service(name, v.chain)
+	var serr *strictError
+	if errors.As(err, &serr) {
+		panic(serr)
+	}
+	return instance, err
+}
+
+func (v *factoryView) ServiceN(name Name) (any, error) {
+	return v.Service(string(name))
+}
+
+func (v *factoryView) Services(names ...string) ([]any, error) {
+	instances := make([]any, len(names))
+	for i, name := range names {
+		instance, err := v.Service(name)
+		if err != nil {
+			return nil, fmt.Errorf("resolving service %d %q: %w", i, name, err)
+		}
+		instances[i] = instance
+	}
+	return instances, nil
+}
+
+func (v *factoryView) Into(name string, target any) error {
+	ptr := reflect.ValueOf(target)
+	if ptr.Kind() != reflect.Pointer || ptr.IsNil() {
+		return fmt.Errorf("target for service %q must be a non-nil pointer, not %T", name, target)
+	}
+	instance, err := v.Service(name)
+	if err != nil {
+		return err
+	}
+	return assign(name, instance, ptr.Elem())
+}
+
+// checkChain reports a resolution of a service already being resolved.
+func (v *factoryView) checkChain(name string) error {
+	if name == v.requester {
+		return &selfError{name: name}
+	}
+	for i, requester := range v.chain {
+		if requester == name {
+			return fmt.Errorf("%s: %w",
+				strings.Join(append(v.chain[i:len(v.chain):len(v.chain)], name), " -> "), ErrCircularDependency)
+		}
+	}
+	return nil
+}
+
+// track records a service if its resolution from the view instantiated it
+// for the first time.
+func (v *factoryView) track(name string) {
+	dic := v.base()
+	dic.RLock()
+	manifested := dic.manifested[name]
+	dic.RUnlock()
+	if !manifested {
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.built = append(v.built, name)
+}
@@ -0,0 +1,150 @@
+package izidic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// WarmupAll attempts to instantiate every registered service, returning the
+// errors for all the services which failed, keyed by service name, or nil if
+// none did. Unlike stopping at the first failure, this reports everything
+// broken in a single pass: services depending on a failed one are attempted
+// too, and report their own errors.
+//
+// Services are scheduled like with WarmupParallel, concurrently and after their
+// dependencies when these were recorded by FreezeWithAnalysis, and one at a time
+// otherwise. The container should be frozen first.
+func (dic *container) WarmupAll() map[string]error {
+	_, errs, _ := dic.schedule(context.Background(), false)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// WarmupParallel instantiates every registered service, running independent
+// factories concurrently while dependents wait for their dependencies, so that
+// slow leaf services like database or remote configuration clients are built in
+// parallel. Like with concurrent resolutions, no factory runs more than once,
+// even for dependencies the schedule does not know about.
+//
+// Dependencies are those recorded by FreezeWithAnalysis. With them, at most
+// runtime.GOMAXPROCS(0) factories run at the same time, unless overridden by
+// WithWarmupConcurrency. Without them, services are instantiated one at a time,
+// in name order, since workers would otherwise mostly wait for each other on
+// shared dependencies.
+//
+// Services depending on a failed one are skipped. Cancelling ctx stops starting
+// new instantiations, and waits for those in progress. The returned error joins
+// the errors for each failed or skipped service, in name order, and the ctx
+// error if it was cancelled before all services were instantiated.
+func (dic *container) WarmupParallel(ctx context.Context) error {
+	names, errs, done := dic.schedule(ctx, true)
+	var joined []error
+	for _, name := range names {
+		switch {
+		case errs[name] != nil:
+			joined = append(joined, fmt.Errorf("service %q: %w", name, errs[name]))
+		case !done[name] && ctx.Err() == nil:
+			joined = append(joined, fmt.Errorf("service %q: skipped after a dependency failed", name))
+		}
+	}
+	if len(done) < len(names) && ctx.Err() != nil {
+		joined = append(joined, ctx.Err())
+	}
+	return errors.Join(joined...)
+}
+
+// schedule instantiates the registered services as described by WarmupParallel,
+// and returns their sorted names, the errors of those which failed, and those
+// which were attempted. With skipFailed, services depending on a failed one are
+// not attempted.
+func (dic *container) schedule(ctx context.Context, skipFailed bool) (names []string, errs map[string]error, done map[string]bool) {
+	dic.RLock()
+	names = make([]string, 0, len(dic.serviceDefs))
+	for name := range dic.serviceDefs {
+		names = append(names, name)
+	}
+	edges, workers := dic.edges, 1
+	if dic.analyzed {
+		workers = runtime.GOMAXPROCS(0)
+		if dic.warmup > 0 {
+			workers = dic.warmup
+		}
+	}
+	dic.RUnlock()
+	sort.Strings(names)
+
+	waiting := make(map[string]int, len(names)) // Dependencies not instantiated yet.
+	dependents := make(map[string][]string, len(names))
+	var ready []string
+	for _, name := range names {
+		for _, dep := range edges[name] {
+			waiting[name]++
+			dependents[dep] = append(dependents[dep], name)
+		}
+		if waiting[name] == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	type outcome struct {
+		name string
+		err  error
+	}
+	jobs, results := make(chan string), make(chan outcome)
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range jobs {
+				_, err := dic.Service(name)
+				results <- outcome{name, err}
+			}
+		}()
+	}
+
+	errs = make(map[string]error)
+	done = make(map[string]bool, len(names))
+	cancelled := ctx.Done()
+	running := 0
+	for {
+		var send chan string
+		var next string
+		if len(ready) > 0 && ctx.Err() == nil {
+			send, next = jobs, ready[0]
+		}
+		if send == nil && running == 0 {
+			break
+		}
+		select {
+		case send <- next:
+			ready = ready[1:]
+			running++
+		case res := <-results:
+			running--
+			done[res.name] = true
+			if res.err != nil {
+				errs[res.name] = res.err
+				if skipFailed {
+					continue
+				}
+			}
+			for _, dependent := range dependents[res.name] {
+				if waiting[dependent]--; waiting[dependent] == 0 {
+					ready = append(ready, dependent)
+				}
+			}
+		case <-cancelled:
+			cancelled = nil // Only stop sending: receive the results in progress.
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return names, errs, done
+}
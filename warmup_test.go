@@ -0,0 +1,179 @@
+package izidic_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/fgm/izidic"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestContainer_WarmupAll(t *testing.T) {
+	errFailed := errors.New("failed")
+	failing := func(izidic.Container) (any, error) { return nil, errFailed }
+
+	dic := izidic.New()
+	dic.Register("s1", s1)
+	dic.Register("s2", s2)
+	dic.Register("f1", failing)
+	dic.Register("f2", dependent("f2", "f1"))
+	dic.Register("f3", dependent("f3", "missing"))
+	dic.Freeze()
+
+	errs := dic.WarmupAll()
+	if len(errs) != 3 {
+		t.Fatalf("got %d errors, but expected 3: %v", len(errs), errs)
+	}
+	for _, name := range []string{"f1", "f2"} {
+		if !errors.Is(errs[name], errFailed) {
+			t.Errorf("got error %v for %s, but expected it to wrap %v", errs[name], name, errFailed)
+		}
+	}
+	if errs["f3"] == nil {
+		t.Error("missing error for f3")
+	}
+	if actual := dic.Unused(); len(actual) != 3 {
+		t.Fatalf("got unused %v, but expected only the failing services", actual)
+	}
+
+	dic = izidic.New()
+	dic.Register("s1", s1)
+	dic.Register("s2", s2)
+	dic.Freeze()
+	if errs := dic.WarmupAll(); errs != nil {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if actual := dic.Unused(); len(actual) != 0 {
+		t.Fatalf("got unused %v after warmup", actual)
+	}
+}
+
+func TestContainer_WarmupAll_SingleRun(t *testing.T) {
+	for _, analyzed := range []bool{false, true} {
+		t.Run(fmt.Sprint("analyzed=", analyzed), func(t *testing.T) {
+			var runs atomic.Int32
+			dic := izidic.New()
+			dic.Register("shared", func(izidic.Container) (any, error) {
+				runs.Add(1)
+				return "shared", nil
+			})
+			for i := 0; i < 16; i++ {
+				name := fmt.Sprint("dependent", i)
+				dic.Register(name, dependent(name, "shared"))
+			}
+			if analyzed {
+				if err := dic.FreezeWithAnalysis(); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			} else {
+				dic.Freeze()
+			}
+			runs.Store(0) // Discovery by FreezeWithAnalysis runs factories in a throwaway copy.
+			if errs := dic.WarmupAll(); errs != nil {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if actual := runs.Load(); actual != 1 {
+				t.Fatalf("got %d runs of the shared factory, but expected 1", actual)
+			}
+		})
+	}
+}
+
+func TestContainer_WarmupParallel_UnrecordedDependencies(t *testing.T) {
+	var runs atomic.Int32
+	dic := izidic.New(izidic.WithWarmupConcurrency(8))
+	dic.Register("db", func(izidic.Container) (any, error) {
+		runs.Add(1)
+		return "db", nil
+	})
+	for i := 0; i < 4; i++ {
+		dic.Register(fmt.Sprint("repo", i), func(dic izidic.Container) (any, error) {
+			return dic.Services("db")
+		})
+	}
+	if err := dic.FreezeWithAnalysis(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	runs.Store(0) // Ignore discovery.
+	if err := dic.WarmupParallel(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual := runs.Load(); actual != 1 {
+		t.Fatalf("got %d runs of the shared factory, but expected 1", actual)
+	}
+}
+
+func TestContainer_WarmupParallel(t *testing.T) {
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	// counted wraps a service to count its factory runs.
+	counted := func(name string, fn izidic.Service) izidic.Service {
+		return func(dic izidic.Container) (any, error) {
+			mu.Lock()
+			calls[name]++
+			mu.Unlock()
+			return fn(dic)
+		}
+	}
+	for _, analyzed := range []bool{false, true} {
+		t.Run(fmt.Sprintf("analyzed %t", analyzed), func(t *testing.T) {
+			calls = make(map[string]int)
+			dic := izidic.New()
+			dic.Register("db", counted("db", dependent("db")))
+			dic.Register("cache", counted("cache", dependent("cache")))
+			dic.Register("repo", counted("repo", dependent("repo", "db", "cache")))
+			dic.Register("api", counted("api", dependent("api", "repo", "cache")))
+			if analyzed {
+				if err := dic.FreezeWithAnalysis(); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				calls = make(map[string]int) // Ignore discovery.
+			} else {
+				dic.Freeze()
+			}
+
+			if err := dic.WarmupParallel(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expected := map[string]int{"api": 1, "cache": 1, "db": 1, "repo": 1}
+			if !cmp.Equal(calls, expected) {
+				t.Fatalf("unexpected factory runs: %s", cmp.Diff(calls, expected))
+			}
+		})
+	}
+}
+
+func TestContainer_WarmupParallel_Errors(t *testing.T) {
+	errFailed := errors.New("failed")
+	dic := izidic.New()
+	dic.Register("f1", func(izidic.Container) (any, error) { return nil, errFailed })
+	dic.Register("f2", dependent("f2", "f1"))
+	dic.Register("s1", s1)
+	if err := dic.FreezeWithAnalysis(); err == nil {
+		t.Fatal("expected analysis to fail")
+	}
+	dic.Freeze()
+	err := dic.WarmupParallel(context.Background())
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("got error %v, but expected it to wrap %v", err, errFailed)
+	}
+
+	dic = izidic.New()
+	dic.Register("s1", s1)
+	dic.Register("s2", s2)
+	if err := dic.FreezeWithAnalysis(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := dic.WarmupParallel(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, but expected it to wrap %v", err, context.Canceled)
+	}
+	if actual := dic.Unused(); len(actual) != 2 {
+		t.Fatalf("got unused %v, but expected no warmup after cancellation", actual)
+	}
+}